@@ -0,0 +1,82 @@
+package v1alpha1
+
+import (
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ImportedAnnotation marks backup objects created from already existing remote backups
+	ImportedAnnotation = "backups.sputnik.systems/imported"
+
+	// RetentionExcludedAnnotation marks backup objects, which are not deleted by schedule retention
+	RetentionExcludedAnnotation = "backups.sputnik.systems/retention-excluded"
+
+	// BackupNameAnnotation keeps original backup name, when it is not valid object name
+	BackupNameAnnotation = "backups.sputnik.systems/backup-name"
+
+	// CreatedAtAnnotation keeps original backup creation time in RFC3339 format
+	CreatedAtAnnotation = "backups.sputnik.systems/created-at"
+
+	// TriggerAnnotation requests immediate backup creation by schedule, when its value is changed
+	TriggerAnnotation = "backups.sputnik.systems/trigger"
+
+	// TargetLostAnnotation keeps time in RFC3339 format, when backup policy target of generated schedule disappeared
+	TargetLostAnnotation = "backups.sputnik.systems/target-lost-at"
+
+	// RestoreNamespacesAnnotation is comma separated list of namespaces, where backup may be restored from, "*" allows all namespaces
+	RestoreNamespacesAnnotation = "backups.sputnik.systems/restore-namespaces"
+
+	// RestoreTargetNamespacesAnnotation is set on namespace, it is comma separated list of namespaces,
+	// which restore objects may restore backups into clickhouse of annotated namespace, "*" allows all namespaces
+	RestoreTargetNamespacesAnnotation = "backups.sputnik.systems/restore-target-namespaces"
+
+	// ScheduleNameLabel and ScheduleUIDLabel mark backup objects owned by schedule
+	ScheduleNameLabel = "backups.sputnik.systems/schedule"
+	ScheduleUIDLabel  = "backups.sputnik.systems/schedule-uid"
+)
+
+// IsImported checks if backup object was created from already existing remote backup.
+func IsImported(obj metav1.Object) bool {
+	return obj.GetAnnotations()[ImportedAnnotation] == "true"
+}
+
+// IsRetentionExcluded checks if backup object is excluded from schedule retention.
+func IsRetentionExcluded(obj metav1.Object) bool {
+	return obj.GetAnnotations()[RetentionExcludedAnnotation] == "true"
+}
+
+// GetCreationTime returns original backup creation time.
+// Object creation timestamp is used, if it is not overwritten by annotation.
+func GetCreationTime(obj metav1.Object) time.Time {
+	if value, ok := obj.GetAnnotations()[CreatedAtAnnotation]; ok {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t
+		}
+	}
+
+	return obj.GetCreationTimestamp().Time
+}
+
+// IsNamespaceListed checks if namespace is listed in comma separated annotation value, "*" lists all namespaces
+func IsNamespaceListed(obj metav1.Object, annotation, ns string) bool {
+	for _, item := range strings.Split(obj.GetAnnotations()[annotation], ",") {
+		if item = strings.TrimSpace(item); item == "*" || item == ns {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsRestoreAllowed checks if backup object may be restored by restore object in given namespace.
+// Backup is always allowed to be restored in its own namespace.
+func IsRestoreAllowed(obj metav1.Object, ns string) bool {
+	if obj.GetNamespace() == ns {
+		return true
+	}
+
+	return IsNamespaceListed(obj, RestoreNamespacesAnnotation, ns)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// NotificationEventCompleted is sent when backup is completed
+	NotificationEventCompleted = "Completed"
+
+	// NotificationEventFailed is sent when backup is moved to one of failed phases
+	NotificationEventFailed = "Failed"
+
+	// NotificationEventStale is sent when schedule newest completed backup is older than max backup age
+	NotificationEventStale = "Stale"
+
+	// NotificationEventFresh is sent when stale schedule got new completed backup
+	NotificationEventFresh = "Fresh"
+
+	// NotificationEventRetentionDeleted is sent when backup is deleted by schedule retention
+	NotificationEventRetentionDeleted = "RetentionDeleted"
+)
+
+// BackupNotificationSpec defines the desired state of BackupNotification
+type BackupNotificationSpec struct {
+	// Events is list of notified events, all events are notified if empty
+	Events []string `json:"events,omitempty"`
+
+	// Engines is list of notified backup engines (clickhouse, dgraph), all engines are notified if empty
+	Engines []string `json:"engines,omitempty"`
+
+	// Schedules is list of notified schedule names in same namespace, all schedules are notified if empty
+	Schedules []string `json:"schedules,omitempty"`
+
+	// Webhook is generic webhook sink, event is posted as json
+	Webhook *BackupNotificationWebhook `json:"webhook,omitempty"`
+
+	// Slack is slack or mattermost compatible incoming webhook sink
+	Slack *BackupNotificationSlack `json:"slack,omitempty"`
+
+	// SMTP is email sink
+	SMTP *BackupNotificationSMTP `json:"smtp,omitempty"`
+}
+
+type BackupNotificationWebhook struct {
+	// URL is webhook url
+	URL string `json:"url,omitempty"`
+
+	// SecretName is name of secret with webhook url, it is used if url is empty
+	SecretName string `json:"secretName,omitempty"`
+
+	// +kubebuilder:default=url
+	// URLKey is secret key with webhook url
+	URLKey string `json:"urlKey,omitempty"`
+
+	// Headers is additional request headers
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+type BackupNotificationSlack struct {
+	// SecretName is name of secret with incoming webhook url
+	SecretName string `json:"secretName"`
+
+	// +kubebuilder:default=url
+	// URLKey is secret key with incoming webhook url
+	URLKey string `json:"urlKey,omitempty"`
+
+	// Channel overrides incoming webhook default channel
+	Channel string `json:"channel,omitempty"`
+
+	// Username overrides incoming webhook default username
+	Username string `json:"username,omitempty"`
+}
+
+type BackupNotificationSMTP struct {
+	// Host is smtp server host
+	Host string `json:"host"`
+
+	// +kubebuilder:default=587
+	// Port is smtp server port
+	Port int `json:"port,omitempty"`
+
+	// From is sender address
+	From string `json:"from"`
+
+	// To is list of recipient addresses
+	To []string `json:"to"`
+
+	// SecretName is name of secret with smtp credentials, authentication is disabled if empty
+	SecretName string `json:"secretName,omitempty"`
+
+	// +kubebuilder:default=username
+	// UsernameKey is secret key with smtp username
+	UsernameKey string `json:"usernameKey,omitempty"`
+
+	// +kubebuilder:default=password
+	// PasswordKey is secret key with smtp password
+	PasswordKey string `json:"passwordKey,omitempty"`
+}
+
+// BackupNotificationStatus defines the observed state of BackupNotification
+type BackupNotificationStatus struct {
+	// Sent is count of sent notifications
+	Sent int `json:"sent,omitempty"`
+
+	// LastSentTime is last notification sending time
+	LastSentTime metav1.Time `json:"lastSentTime,omitempty"`
+
+	// Error is error message if last notification sending failed
+	Error string `json:"error,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Sent",type="integer",JSONPath=".status.sent",description="sent notifications count"
+//+kubebuilder:printcolumn:name="Last Sent",type="date",JSONPath=".status.lastSentTime"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// BackupNotification is the Schema for the backupnotifications API
+type BackupNotification struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BackupNotificationSpec   `json:"spec,omitempty"`
+	Status BackupNotificationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// BackupNotificationList contains a list of BackupNotification
+type BackupNotificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BackupNotification `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BackupNotification{}, &BackupNotificationList{})
+}
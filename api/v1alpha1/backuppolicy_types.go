@@ -0,0 +1,115 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupPolicyLabel marks backup schedules created by backup policy
+const BackupPolicyLabel = "backups.sputnik.systems/backup-policy"
+
+const (
+	// BackupPolicyTargetService and BackupPolicyTargetPod are discovered object kinds
+	BackupPolicyTargetService = "Service"
+	BackupPolicyTargetPod     = "Pod"
+)
+
+// BackupPolicySpec defines the desired state of BackupPolicy
+type BackupPolicySpec struct {
+	// TargetKind is kind of discovered objects, Service or Pod
+	//+kubebuilder:validation:Enum=Service;Pod
+	//+kubebuilder:default=Service
+	TargetKind string `json:"targetKind,omitempty"`
+
+	// Selector is label selector of discovered objects
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// NamespaceSelector is specify namespaces, where objects are discovered, all namespaces are selected if empty
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// DeletionGracePeriod is time, while schedule of disappeared object is kept suspended before its deletion,
+	// so restarted or recreated objects keep their schedules. Default is 1h, 0s deletes schedule at once.
+	DeletionGracePeriod string `json:"deletionGracePeriod,omitempty"`
+
+	// ClickHouse is clickhouse backup schedule template.
+	// String fields are go templates with .Name, .Namespace, .Target, .Host and .Labels (target labels) values.
+	ClickHouse *ClickHouseBackupScheduleSpec `json:"clickhouse,omitempty"`
+
+	// Dgraph is dgraph backup schedule template.
+	// String fields are go templates with .Name, .Namespace, .Target, .Host and .Labels (target labels) values.
+	Dgraph *DgraphBackupScheduleSpec `json:"dgraph,omitempty"`
+}
+
+// BackupPolicyStatus defines the observed state of BackupPolicy
+type BackupPolicyStatus struct {
+	// Targets is list of discovered objects in namespace/name format
+	Targets []string `json:"targets,omitempty"`
+
+	// Discovered is count of discovered objects
+	Discovered int `json:"discovered,omitempty"`
+
+	// LastSyncTime is last backup schedules synchronization time
+	LastSyncTime metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Error is error message if last synchronization failed
+	Error string `json:"error,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.targetKind",description="kind of discovered objects"
+//+kubebuilder:printcolumn:name="Discovered",type="integer",JSONPath=".status.discovered",description="count of discovered objects"
+//+kubebuilder:printcolumn:name="Last Sync",type="date",JSONPath=".status.lastSyncTime"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// BackupPolicy is the Schema for the backuppolicies API
+type BackupPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BackupPolicySpec   `json:"spec,omitempty"`
+	Status BackupPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// BackupPolicyList contains a list of BackupPolicy
+type BackupPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BackupPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BackupPolicy{}, &BackupPolicyList{})
+}
+
+// AsOwner returns controller owner reference, namespaced schedules may be owned by cluster scoped object
+func (cr *BackupPolicy) AsOwner() []metav1.OwnerReference {
+	return []metav1.OwnerReference{*metav1.NewControllerRef(cr, GroupVersion.WithKind("BackupPolicy"))}
+}
+
+// GetTargetKind returns kind of discovered objects
+func (cr *BackupPolicy) GetTargetKind() string {
+	if cr.Spec.TargetKind == "" {
+		return BackupPolicyTargetService
+	}
+
+	return cr.Spec.TargetKind
+}
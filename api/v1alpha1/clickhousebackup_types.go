@@ -28,6 +28,18 @@ type ClickHouseBackupSpec struct {
 	// ApiAddress is requests sending endpoint
 	ApiAddress string `json:"apiAddress"`
 
+	// PodSelector is selector of pods with clickhouse-backup api, api address service endpoints are used if empty
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// Auth is specify clickhouse-backup api basic auth credentials
+	Auth *ClickHouseBackupApiAuth `json:"auth,omitempty"`
+
+	// TLS is specify clickhouse-backup api tls settings
+	TLS *ClickHouseBackupApiTLS `json:"tls,omitempty"`
+
+	// RequestTimeout is single clickhouse-backup api request timeout
+	RequestTimeout string `json:"requestTimeout,omitempty"`
+
 	// ExponentialBackOff is specify exponential backoff time settings for backup creation flow
 	ExponentialBackOff *ExponentialBackOffSpec `json:"exponentialBackOff,omitempty"`
 
@@ -36,8 +48,58 @@ type ClickHouseBackupSpec struct {
 
 	// UploadParams is optional backup uploading query params
 	UploadParams map[string]string `json:"uploadParams,omitempty"`
+
+	// DeletionPolicy is specify what happens with backup data when object is deleted
+	//+kubebuilder:validation:Enum=Retain;Delete;DeleteLocalOnly
+	//+kubebuilder:default=Delete
+	DeletionPolicy ClickHouseBackupDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Hooks is actions executed before and after backup creation
+	Hooks *BackupHooks `json:"hooks,omitempty"`
+}
+
+// ClickHouseBackupApiAuth defines clickhouse-backup api basic auth credentials
+type ClickHouseBackupApiAuth struct {
+	// SecretName is name of secret with API_USERNAME and API_PASSWORD values
+	SecretName string `json:"secretName"`
+
+	// UsernameKey is secret key with username
+	//+kubebuilder:default=username
+	UsernameKey string `json:"usernameKey,omitempty"`
+
+	// PasswordKey is secret key with password
+	//+kubebuilder:default=password
+	PasswordKey string `json:"passwordKey,omitempty"`
+}
+
+// ClickHouseBackupApiTLS defines clickhouse-backup api tls settings
+type ClickHouseBackupApiTLS struct {
+	// CASecretName is name of secret with api server CA certificate
+	CASecretName string `json:"caSecretName,omitempty"`
+
+	// CAKey is secret key with CA certificate
+	//+kubebuilder:default=ca.crt
+	CAKey string `json:"caKey,omitempty"`
+
+	// InsecureSkipVerify disables api server certificate verification
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
 }
 
+// ClickHouseBackupDeletionPolicy describes how backup data is handled on object deletion
+type ClickHouseBackupDeletionPolicy string
+
+const (
+	// ClickHouseBackupDeletionPolicyRetain keeps local and remote backup data.
+	// Local data of backup in CreateFailed phase is partially created, so it is removed anyway.
+	ClickHouseBackupDeletionPolicyRetain ClickHouseBackupDeletionPolicy = "Retain"
+
+	// ClickHouseBackupDeletionPolicyDelete removes local and remote backup data
+	ClickHouseBackupDeletionPolicyDelete ClickHouseBackupDeletionPolicy = "Delete"
+
+	// ClickHouseBackupDeletionPolicyDeleteLocalOnly removes only local backup data
+	ClickHouseBackupDeletionPolicyDeleteLocalOnly ClickHouseBackupDeletionPolicy = "DeleteLocalOnly"
+)
+
 // ClickHouseBackupStatus defines the observed state of ClickHouseBackup
 type ClickHouseBackupStatus struct {
 	// Phase is current state of underlying operation
@@ -46,8 +108,26 @@ type ClickHouseBackupStatus struct {
 	// Api is specify where requests will be send
 	Api ClickHouseBackupStatusApi `json:"api,omitempty"`
 
+	// OperationID is current clickhouse-backup operation id, if it is supported by api
+	OperationID string `json:"operationId,omitempty"`
+
 	// Error is error message if backup creationg failed
 	Error string `json:"error,omitempty"`
+
+	// Size is uploaded backup size in bytes
+	Size int64 `json:"size,omitempty"`
+
+	// CompletionTime is time, when backup moved to completed phase
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Hooks is executed hooks outcomes
+	Hooks []BackupHookStatus `json:"hooks,omitempty"`
+
+	// TraceID is trace id of last reconcile, which processed backup object
+	TraceID string `json:"traceId,omitempty"`
+
+	// Conditions is list of backup object conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 type ClickHouseBackupStatusApi struct {
@@ -56,6 +136,15 @@ type ClickHouseBackupStatusApi struct {
 
 	// Hostname is Hostname header value
 	Hostname string `json:"Hostname,omitempty"`
+
+	// PodName is name of pod, where backup is created
+	PodName string `json:"podName,omitempty"`
+
+	// PodUID is uid of pod, where backup is created
+	PodUID string `json:"podUid,omitempty"`
+
+	// RestartCount is pod containers restarts count, when backup creation started
+	RestartCount int32 `json:"restartCount,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -84,3 +173,12 @@ type ClickHouseBackupList struct {
 func init() {
 	SchemeBuilder.Register(&ClickHouseBackup{}, &ClickHouseBackupList{})
 }
+
+// BackupName returns backup name used by clickhouse-backup
+func (cr *ClickHouseBackup) BackupName() string {
+	if name, ok := cr.Annotations[BackupNameAnnotation]; ok && name != "" {
+		return name
+	}
+
+	return cr.Name
+}
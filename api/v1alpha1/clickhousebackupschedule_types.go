@@ -17,8 +17,6 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"strings"
-
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 )
@@ -31,9 +29,33 @@ type ClickHouseBackupScheduleSpec struct {
 	// Schedule is schedule info in github.com/robfig/cron supported notation
 	Schedule string `json:"schedule"`
 
+	// TimeZone is IANA time zone name of schedule, pod local time zone is used if empty
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Jitter is max delay of scheduled backups creation, actual delay is derived from schedule UID
+	Jitter string `json:"jitter,omitempty"`
+
 	// Retention is specify how long should to keep backups
 	Retention string `json:"retention,omitempty"`
 
+	// Suspend is stop scheduled backups creation, retention keeps running
+	Suspend bool `json:"suspend,omitempty"`
+
+	// SuspendRetention is stop schedule backups retention
+	SuspendRetention bool `json:"suspendRetention,omitempty"`
+
+	// BlackoutWindows is list of periods, when scheduled backups are not created
+	BlackoutWindows []BlackoutWindow `json:"blackoutWindows,omitempty"`
+
+	// MaxBackupAge is max allowed age of newest completed backup, schedule is marked as stale if exceeded
+	MaxBackupAge string `json:"maxBackupAge,omitempty"`
+
+	// Audit is specify periodic comparison of backup objects with remote storage
+	Audit *BackupAuditSpec `json:"audit,omitempty"`
+
+	// BackupTemplate is specify metadata of created backup objects
+	BackupTemplate *BackupTemplate `json:"backupTemplate,omitempty"`
+
 	// Backup is specify clickhouse backup options
 	Backup ClickHouseBackupSpec `json:"backup"`
 }
@@ -41,15 +63,46 @@ type ClickHouseBackupScheduleSpec struct {
 // ClickHouseBackupScheduleStatus defines the observed state of ClickHouseBackupSchedule
 type ClickHouseBackupScheduleStatus struct {
 	ScheduleTaskID   int         `json:"scheduleTaskId,omitempty"`
-	RetentionTaskID  int         `json:"retentionTaskId,omitempty"`
+	AuditTaskID      int         `json:"auditTaskId,omitempty"`
+	FreshnessTaskID  int         `json:"freshnessTaskId,omitempty"`
 	ActiveGeneration int64       `json:"activeGeneration,omitempty"`
 	UpdatedAt        metav1.Time `json:"updatedTime,omitempty"`
+
+	BackupScheduleHistory `json:",inline"`
+
+	// Audit is last remote storage audit result
+	Audit *BackupAuditStatus `json:"audit,omitempty"`
+
+	// RemoteBackups is remote names of schedule backups, which data is deleted together with backup objects.
+	// It is kept only when audit deletes orphans, other remote backups are never deleted by audit.
+	RemoteBackups []string `json:"remoteBackups,omitempty"`
+
+	// Skipped is info about schedule ticks skipped because of suspension, blackout window or backup name conflict
+	Skipped *BackupSkipStatus `json:"skipped,omitempty"`
+
+	// LastTrigger is last handled trigger annotation value
+	LastTrigger string `json:"lastTrigger,omitempty"`
+
+	// LastTriggeredBackup is name of backup object created by last handled trigger
+	LastTriggeredBackup string `json:"lastTriggeredBackup,omitempty"`
+
+	// LastCompletedTime is creation time of newest completed backup
+	LastCompletedTime *metav1.Time `json:"lastCompletedTime,omitempty"`
+
+	// Conditions is list of schedule conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule",description="backup objects creation schedule"
 //+kubebuilder:printcolumn:name="Retention",type="string",JSONPath=".spec.retention",description="backup objects retention period"
+//+kubebuilder:printcolumn:name="Suspend",type="boolean",JSONPath=".spec.suspend",description="scheduled backups creation is suspended"
+//+kubebuilder:printcolumn:name="Active",type="integer",JSONPath=".status.active",description="count of not finished backup objects"
+//+kubebuilder:printcolumn:name="Retained",type="integer",JSONPath=".status.retained",description="count of schedule backup objects",priority=1
+//+kubebuilder:printcolumn:name="Last Schedule",type="date",JSONPath=".status.lastScheduleTime",description="newest backup object creation time"
+//+kubebuilder:printcolumn:name="Last Success",type="date",JSONPath=".status.lastSuccessfulTime",description="newest completed backup completion time"
+//+kubebuilder:printcolumn:name="Fresh",type="string",JSONPath=".status.conditions[?(@.type==\"Fresh\")].status",description="newest completed backup is younger than max backup age"
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // ClickHouseBackupSchedule is the Schema for the clickhousebackupschedules API
@@ -87,16 +140,6 @@ func (cr *ClickHouseBackupSchedule) AsOwner() []metav1.OwnerReference {
 	}
 }
 
-func (cr ClickHouseBackupSchedule) Annotations() map[string]string {
-	annotations := make(map[string]string)
-	for annotation, value := range cr.ObjectMeta.Annotations {
-		if !strings.HasPrefix(annotation, "kubectl.kubernetes.io/") {
-			annotations[annotation] = value
-		}
-	}
-	return annotations
-}
-
 // IsNeedUpdate returns true if resource must be updated
 func (cr *ClickHouseBackupSchedule) IsNeedUpdate(startedAt *metav1.Time) bool {
 	if cr.Generation != cr.Status.ActiveGeneration {
@@ -0,0 +1,117 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClickHouseRestoreSpec defines the desired state of ClickHouseRestore
+type ClickHouseRestoreSpec struct {
+	// Backup is restored clickhouse backup object, it is set by restore schedule for scheduled restores
+	Backup BackupReference `json:"backup,omitempty"`
+
+	// Target is clickhouse-backup api, where backup is restored.
+	// Backup object api is used if empty, it is allowed only for backup in restore object namespace.
+	// Target clickhouse-backup must use the same remote storage as backup object api.
+	Target *ClickHouseRestoreTarget `json:"target,omitempty"`
+
+	// DatabaseMapping is map of backup database names to restored database names
+	DatabaseMapping map[string]string `json:"databaseMapping,omitempty"`
+
+	// TableMapping is map of backup table names to restored table names
+	TableMapping map[string]string `json:"tableMapping,omitempty"`
+
+	// DownloadParams is optional backup downloading query params
+	DownloadParams map[string]string `json:"downloadParams,omitempty"`
+
+	// RestoreParams is optional backup restoring query params, e.g. table, schema, data or rm
+	RestoreParams map[string]string `json:"restoreParams,omitempty"`
+
+	// ExponentialBackOff is specify exponential backoff time settings for restore flow
+	ExponentialBackOff *ExponentialBackOffSpec `json:"exponentialBackOff,omitempty"`
+}
+
+// ClickHouseRestoreTarget defines clickhouse-backup api, where backup is restored
+type ClickHouseRestoreTarget struct {
+	// ApiAddress is requests sending endpoint
+	ApiAddress string `json:"apiAddress"`
+
+	// PodSelector is selector of pods with clickhouse-backup api, api address service endpoints are used if empty
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// Auth is specify clickhouse-backup api basic auth credentials
+	Auth *ClickHouseBackupApiAuth `json:"auth,omitempty"`
+
+	// TLS is specify clickhouse-backup api tls settings
+	TLS *ClickHouseBackupApiTLS `json:"tls,omitempty"`
+
+	// RequestTimeout is single clickhouse-backup api request timeout
+	RequestTimeout string `json:"requestTimeout,omitempty"`
+}
+
+// ClickHouseRestoreStatus defines the observed state of ClickHouseRestore
+type ClickHouseRestoreStatus struct {
+	// Phase is current state of underlying operation
+	Phase string `json:"phase,omitempty"`
+
+	// BackupName is restored backup name used by clickhouse-backup
+	BackupName string `json:"backupName,omitempty"`
+
+	// Api is specify where requests will be send
+	Api ClickHouseBackupStatusApi `json:"api,omitempty"`
+
+	// OperationID is current clickhouse-backup operation id, if it is supported by api
+	OperationID string `json:"operationId,omitempty"`
+
+	// Error is error message if restore failed
+	Error string `json:"error,omitempty"`
+
+	// CompletionTime is time, when restore moved to completed phase
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// TraceID is trace id of last reconcile, which processed restore object
+	TraceID string `json:"traceId,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Backup",type="string",JSONPath=".spec.backup.name",description="restored backup object"
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="restore phase"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ClickHouseRestore is the Schema for the clickhouserestores API
+type ClickHouseRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClickHouseRestoreSpec   `json:"spec,omitempty"`
+	Status ClickHouseRestoreStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClickHouseRestoreList contains a list of ClickHouseRestore
+type ClickHouseRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClickHouseRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClickHouseRestore{}, &ClickHouseRestoreList{})
+}
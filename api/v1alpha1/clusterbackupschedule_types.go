@@ -0,0 +1,87 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterScheduleLabel marks backup schedules created by cluster backup schedule
+const ClusterScheduleLabel = "backups.sputnik.systems/cluster-schedule"
+
+// ClusterBackupScheduleSpec defines the desired state of ClusterBackupSchedule
+type ClusterBackupScheduleSpec struct {
+	// NamespaceSelector is specify namespaces, where backup schedules are created, all namespaces are selected if empty
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// ClickHouse is clickhouse backup schedule template.
+	// String fields are go templates with .Name, .Namespace and .Labels (namespace labels) values.
+	ClickHouse *ClickHouseBackupScheduleSpec `json:"clickhouse,omitempty"`
+
+	// Dgraph is dgraph backup schedule template.
+	// String fields are go templates with .Name, .Namespace and .Labels (namespace labels) values.
+	Dgraph *DgraphBackupScheduleSpec `json:"dgraph,omitempty"`
+}
+
+// ClusterBackupScheduleStatus defines the observed state of ClusterBackupSchedule
+type ClusterBackupScheduleStatus struct {
+	// Namespaces is list of namespaces, where backup schedules are created
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Scheduled is count of selected namespaces
+	Scheduled int `json:"scheduled,omitempty"`
+
+	// LastSyncTime is last backup schedules synchronization time
+	LastSyncTime metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Error is error message if last synchronization failed
+	Error string `json:"error,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Scheduled",type="integer",JSONPath=".status.scheduled",description="count of namespaces with backup schedules"
+//+kubebuilder:printcolumn:name="Last Sync",type="date",JSONPath=".status.lastSyncTime"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ClusterBackupSchedule is the Schema for the clusterbackupschedules API
+type ClusterBackupSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterBackupScheduleSpec   `json:"spec,omitempty"`
+	Status ClusterBackupScheduleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterBackupScheduleList contains a list of ClusterBackupSchedule
+type ClusterBackupScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterBackupSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterBackupSchedule{}, &ClusterBackupScheduleList{})
+}
+
+// AsOwner returns controller owner reference, namespaced schedules may be owned by cluster scoped object
+func (cr *ClusterBackupSchedule) AsOwner() []metav1.OwnerReference {
+	return []metav1.OwnerReference{*metav1.NewControllerRef(cr, GroupVersion.WithKind("ClusterBackupSchedule"))}
+}
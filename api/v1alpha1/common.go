@@ -5,8 +5,177 @@ import (
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// BackupAuditSpec defines remote storage audit settings
+type BackupAuditSpec struct {
+	// Schedule is audit schedule in github.com/robfig/cron supported notation
+	//+kubebuilder:default="@hourly"
+	Schedule string `json:"schedule,omitempty"`
+
+	// DeleteOrphansAfter is specify how old should be remote backup without backup object to be deleted, orphans are not deleted if empty.
+	// Only remote backups of schedule backups, which deletion policy removes remote data, are deleted.
+	DeleteOrphansAfter string `json:"deleteOrphansAfter,omitempty"`
+}
+
+// BackupAuditStatus defines the observed state of remote storage audit
+type BackupAuditStatus struct {
+	// LastAuditTime is last audit execution time
+	LastAuditTime metav1.Time `json:"lastAuditTime,omitempty"`
+
+	// Missing is count of completed backup objects, which data not found in remote storage
+	Missing int `json:"missing,omitempty"`
+
+	// Orphans is list of remote backups, which have no backup objects
+	Orphans []string `json:"orphans,omitempty"`
+
+	// Error is error message if last audit failed
+	Error string `json:"error,omitempty"`
+}
+
+// GetSchedule returns audit schedule
+func (a *BackupAuditSpec) GetSchedule() string {
+	if a.Schedule == "" {
+		return "@hourly"
+	}
+
+	return a.Schedule
+}
+
+// GetDeleteOrphansAfter returns parsed orphans retention, zero value means orphans should be kept
+func (a *BackupAuditSpec) GetDeleteOrphansAfter() (time.Duration, error) {
+	if a.DeleteOrphansAfter == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(a.DeleteOrphansAfter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse DeleteOrphansAfter: %w", err)
+	}
+
+	return d, nil
+}
+
+// BlackoutWindow defines period, when scheduled backups are not created.
+// Window is set either by cron schedule of its start with duration or by start and end time.
+type BlackoutWindow struct {
+	// Schedule is window start schedule in github.com/robfig/cron supported notation
+	Schedule string `json:"schedule,omitempty"`
+
+	// Duration is window length, required with schedule
+	Duration string `json:"duration,omitempty"`
+
+	// TimeZone is IANA time zone name of window schedule, UTC by default
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Start is window start time in RFC3339 format
+	Start *metav1.Time `json:"start,omitempty"`
+
+	// End is window end time in RFC3339 format
+	End *metav1.Time `json:"end,omitempty"`
+}
+
+// BackupTemplate defines metadata of backup objects created by schedule
+type BackupTemplate struct {
+	// Metadata is labels and annotations added to backup objects
+	Metadata BackupTemplateMetadata `json:"metadata,omitempty"`
+
+	// NameTemplate is go template of backup object name with Schedule, Namespace and Time fields.
+	// Time is schedule tick time, so the same tick always gives the same name.
+	// Default is {{.Schedule}}-{{.Time.Unix}}
+	NameTemplate string `json:"nameTemplate,omitempty"`
+}
+
+// BackupTemplateMetadata defines backup object labels and annotations
+type BackupTemplateMetadata struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// BackupSkipStatus defines the observed state of skipped schedule ticks
+type BackupSkipStatus struct {
+	// Count is count of skipped schedule ticks
+	Count int64 `json:"count,omitempty"`
+
+	// LastSkippedTime is last skipped tick time
+	LastSkippedTime metav1.Time `json:"lastSkippedTime,omitempty"`
+
+	// Reason is last skipped tick reason
+	Reason string `json:"reason,omitempty"`
+}
+
+// BackupScheduleHistory defines the observed state of backup objects created by schedule
+type BackupScheduleHistory struct {
+	// LastScheduleTime is creation time of newest backup object created by schedule
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// LastSuccessfulTime is completion time of newest completed backup
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// NextScheduleTime is next backup creation time, empty if schedule is suspended
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// RecentRuns is list of newest schedule backup objects
+	RecentRuns []BackupRun `json:"recentRuns,omitempty"`
+
+	// Active is count of schedule backup objects, which are not finished yet
+	Active int `json:"active,omitempty"`
+
+	// Retained is count of all schedule backup objects
+	Retained int `json:"retained,omitempty"`
+}
+
+// BackupRun is short info about backup object created by schedule
+type BackupRun struct {
+	// Name is backup object name
+	Name string `json:"name"`
+
+	// Phase is backup object phase
+	Phase string `json:"phase,omitempty"`
+
+	// StartTime is backup creation time
+	StartTime metav1.Time `json:"startTime,omitempty"`
+
+	// Duration is time between backup creation and completion
+	Duration string `json:"duration,omitempty"`
+
+	// Size is backup size in bytes, if it is known
+	Size int64 `json:"size,omitempty"`
+}
+
+// IsActive returns true if given time is inside of window
+func (w *BlackoutWindow) IsActive(now time.Time) (bool, error) {
+	if w.Schedule == "" {
+		if w.Start == nil || w.End == nil {
+			return false, fmt.Errorf("blackout window requires schedule with duration or start with end")
+		}
+
+		return !now.Before(w.Start.Time) && now.Before(w.End.Time), nil
+	}
+
+	d, err := time.ParseDuration(w.Duration)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse blackout window duration: %w", err)
+	}
+
+	loc, err := time.LoadLocation(w.TimeZone)
+	if err != nil {
+		return false, fmt.Errorf("failed to load blackout window time zone: %w", err)
+	}
+
+	s, err := cron.ParseStandard(w.Schedule)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse blackout window schedule: %w", err)
+	}
+
+	// window is active, when it has started during last duration
+	start := s.Next(now.In(loc).Add(-d))
+
+	return !start.After(now), nil
+}
+
 type ExponentialBackOffSpec struct {
 	InitialInterval string `json:"initialInterval,omitempty"`
 	MaxInterval     string `json:"maxInterval,omitempty"`
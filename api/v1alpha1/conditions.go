@@ -0,0 +1,13 @@
+package v1alpha1
+
+const (
+	// ConditionMissing is set when backup data not found in remote storage
+	ConditionMissing = "Missing"
+
+	// ConditionFresh is set when newest completed schedule backup is younger than max backup age
+	ConditionFresh = "Fresh"
+
+	// ReasonFresh and ReasonStale are fresh condition reasons
+	ReasonFresh = "Fresh"
+	ReasonStale = "Stale"
+)
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -40,17 +41,80 @@ type DgraphBackupSpec struct {
 	// Region is s3 storage region
 	Region string `json:"region,omitempty"`
 
-	// Secrets is list of secret abstraction names
+	// Secrets is list of secret abstraction names with accessKey, secretKey and sessionToken keys.
+	// Deprecated: use credentials instead
 	Secrets []string `json:"secrets,omitempty"`
 
+	// Credentials defines remote storage credentials provider
+	Credentials *StorageCredentials `json:"credentials,omitempty"`
+
 	// Anonymous if credentials is not required
 	Anonymous bool `json:"anonymous,omitempty"`
+
+	// Hooks is actions executed before and after backup creation
+	Hooks *BackupHooks `json:"hooks,omitempty"`
+}
+
+// StorageCredentials defines remote storage credentials provider, only one provider may be set
+type StorageCredentials struct {
+	// Secret reads credentials from secret keys in backup object namespace
+	Secret *StorageCredentialsSecret `json:"secret,omitempty"`
+
+	// File reads credentials from files mounted into operator pod
+	File *StorageCredentialsFile `json:"file,omitempty"`
+
+	// WebIdentity exchanges web identity token file for temporary credentials, as IRSA does
+	WebIdentity *StorageCredentialsWebIdentity `json:"webIdentity,omitempty"`
+
+	// Environment reads credentials from operator AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY
+	// and AWS_SESSION_TOKEN environment variables
+	Environment bool `json:"environment,omitempty"`
+}
+
+// StorageCredentialsSecret defines credentials secret keys
+type StorageCredentialsSecret struct {
+	AccessKey    corev1.SecretKeySelector  `json:"accessKey"`
+	SecretKey    corev1.SecretKeySelector  `json:"secretKey"`
+	SessionToken *corev1.SecretKeySelector `json:"sessionToken,omitempty"`
+}
+
+// StorageCredentialsFile defines credentials files paths
+type StorageCredentialsFile struct {
+	AccessKeyPath    string `json:"accessKeyPath"`
+	SecretKeyPath    string `json:"secretKeyPath"`
+	SessionTokenPath string `json:"sessionTokenPath,omitempty"`
+}
+
+// StorageCredentialsWebIdentity defines web identity role, AWS_ROLE_ARN and
+// AWS_WEB_IDENTITY_TOKEN_FILE operator environment variables are used by default
+type StorageCredentialsWebIdentity struct {
+	RoleARN     string `json:"roleArn,omitempty"`
+	TokenFile   string `json:"tokenFile,omitempty"`
+	SessionName string `json:"sessionName,omitempty"`
 }
 
 // DgraphBackupStatus defines the observed state of DgraphBackup
 type DgraphBackupStatus struct {
 	Phase          string                          `json:"phase,omitempty"`
 	ExportResponse DgraphBackupStatusExportResonse `json:"exportResponse,omitempty"`
+
+	// Error is error message if backup export failed
+	Error string `json:"error,omitempty"`
+
+	// CompletionTime is time, when backup moved to completed phase
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Size is exported files size in bytes, it is not set, when destination listing fails
+	Size int64 `json:"size,omitempty"`
+
+	// Hooks is executed hooks outcomes
+	Hooks []BackupHookStatus `json:"hooks,omitempty"`
+
+	// TraceID is trace id of last reconcile, which processed backup object
+	TraceID string `json:"traceId,omitempty"`
+
+	// Conditions is list of backup object conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 type DgraphBackupStatusExportResonse struct {
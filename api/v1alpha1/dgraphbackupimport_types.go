@@ -0,0 +1,87 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DgraphBackupImportSpec defines the desired state of DgraphBackupImport
+type DgraphBackupImportSpec struct {
+	// Interval is specify how often remote storage should be rescanned, if empty storage is scanned once
+	Interval string `json:"interval,omitempty"`
+
+	// ScheduleName is name of schedule object in same namespace, which will own imported backups
+	ScheduleName string `json:"scheduleName,omitempty"`
+
+	// ExcludeFromRetention marks imported backups, so they and their remote data are not deleted by schedule retention
+	ExcludeFromRetention bool `json:"excludeFromRetention,omitempty"`
+
+	// Backup is specify dgraph backup options, which will be set in imported objects
+	Backup DgraphBackupSpec `json:"backup"`
+}
+
+// DgraphBackupImportStatus defines the observed state of DgraphBackupImport
+type DgraphBackupImportStatus struct {
+	// Phase is current state of underlying operation
+	Phase string `json:"phase,omitempty"`
+
+	// Imported is count of backup objects created by last scan
+	Imported int `json:"imported,omitempty"`
+
+	// Found is count of backups found in remote storage by last scan
+	Found int `json:"found,omitempty"`
+
+	// LastScanTime is last remote storage scan time
+	LastScanTime metav1.Time `json:"lastScanTime,omitempty"`
+
+	// Error is error message if last scan failed
+	Error string `json:"error,omitempty"`
+
+	// ImportedBackups is remote names of backups imported by this object, which still exist in remote storage.
+	// Backup objects deleted after import are not created again.
+	ImportedBackups []string `json:"importedBackups,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="remote storage scan phase"
+//+kubebuilder:printcolumn:name="Found",type="integer",JSONPath=".status.found",description="backups found in remote storage"
+//+kubebuilder:printcolumn:name="Last Scan",type="date",JSONPath=".status.lastScanTime"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// DgraphBackupImport is the Schema for the dgraphbackupimports API
+type DgraphBackupImport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DgraphBackupImportSpec   `json:"spec,omitempty"`
+	Status DgraphBackupImportStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DgraphBackupImportList contains a list of DgraphBackupImport
+type DgraphBackupImportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DgraphBackupImport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DgraphBackupImport{}, &DgraphBackupImportList{})
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DgraphRestoreSpec defines the desired state of DgraphRestore
+type DgraphRestoreSpec struct {
+	// Backup is restored dgraph backup object, it is set by restore schedule for scheduled restores
+	Backup BackupReference `json:"backup,omitempty"`
+
+	// AlphaAddress is target dgraph alpha grpc address, e.g. dgraph-alpha:9080
+	AlphaAddress string `json:"alphaAddress"`
+
+	// ZeroAddress is target dgraph zero grpc address, e.g. dgraph-zero:5080
+	ZeroAddress string `json:"zeroAddress"`
+
+	// Image is dgraph image used by live loader job
+	//+kubebuilder:default="dgraph/dgraph:v21.03.2"
+	Image string `json:"image,omitempty"`
+
+	// Region is s3 storage region, backup object region is used if empty
+	Region string `json:"region,omitempty"`
+
+	// Credentials defines remote storage credentials provider.
+	// Backup object credentials are used if neither credentials nor anonymous is set,
+	// it is allowed only for backup in restore object namespace.
+	Credentials *StorageCredentials `json:"credentials,omitempty"`
+
+	// Anonymous if credentials is not required
+	Anonymous bool `json:"anonymous,omitempty"`
+}
+
+// DgraphRestoreStatus defines the observed state of DgraphRestore
+type DgraphRestoreStatus struct {
+	// Phase is current state of underlying operation
+	Phase string `json:"phase,omitempty"`
+
+	// JobName is name of live loader job
+	JobName string `json:"jobName,omitempty"`
+
+	// Error is error message if restore failed
+	Error string `json:"error,omitempty"`
+
+	// CompletionTime is time, when restore moved to completed phase
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// TraceID is trace id of last reconcile, which processed restore object
+	TraceID string `json:"traceId,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Backup",type="string",JSONPath=".spec.backup.name",description="restored backup object"
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="restore phase"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// DgraphRestore is the Schema for the dgraphrestores API
+type DgraphRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DgraphRestoreSpec   `json:"spec,omitempty"`
+	Status DgraphRestoreStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DgraphRestoreList contains a list of DgraphRestore
+type DgraphRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DgraphRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DgraphRestore{}, &DgraphRestoreList{})
+}
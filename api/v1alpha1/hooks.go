@@ -0,0 +1,122 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupHooks defines actions executed before and after backup creation
+type BackupHooks struct {
+	// Pre hooks are executed in order before backup creation
+	Pre []BackupHook `json:"pre,omitempty"`
+
+	// Post hooks are executed in order, when backup is completed or failed
+	Post []BackupHook `json:"post,omitempty"`
+}
+
+// BackupHook defines single hook action, only one of exec, http and sql may be set
+type BackupHook struct {
+	// Name is hook name used in status
+	Name string `json:"name"`
+
+	// Exec runs command in pod container through pods/exec subresource
+	Exec *BackupHookExec `json:"exec,omitempty"`
+
+	// HTTP sends http request
+	HTTP *BackupHookHTTP `json:"http,omitempty"`
+
+	// SQL runs statement through clickhouse http interface
+	SQL *BackupHookSQL `json:"sql,omitempty"`
+
+	// Timeout is hook execution timeout
+	//+kubebuilder:default="30s"
+	Timeout string `json:"timeout,omitempty"`
+
+	// OnError is specify what happens with backup, when hook fails
+	//+kubebuilder:validation:Enum=Fail;Continue
+	//+kubebuilder:default=Fail
+	OnError BackupHookOnError `json:"onError,omitempty"`
+}
+
+// BackupHookExec defines command executed in pod container
+type BackupHookExec struct {
+	// Pod is name of pod in backup object namespace
+	Pod string `json:"pod,omitempty"`
+
+	// PodSelector selects pod by labels, first ready pod is used.
+	// ClickHouse backup target pod is used, when neither pod nor selector is set.
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// Container is container name, pod default container is used if empty
+	Container string `json:"container,omitempty"`
+
+	// Command is command with arguments, it is not executed in shell
+	Command []string `json:"command"`
+}
+
+// BackupHookHTTP defines http request, hook succeeds on 2xx response status
+type BackupHookHTTP struct {
+	// URL is request url, namespace may be skipped for services in backup object namespace
+	URL string `json:"url"`
+
+	// Method is request method
+	//+kubebuilder:default=POST
+	Method string `json:"method,omitempty"`
+
+	// Headers is request headers
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Body is request body
+	Body string `json:"body,omitempty"`
+}
+
+// BackupHookSQL defines statement executed through clickhouse http interface
+type BackupHookSQL struct {
+	// Address is clickhouse http interface address, e.g. http://clickhouse:8123
+	Address string `json:"address"`
+
+	// Query is executed statement, e.g. SYSTEM FLUSH LOGS
+	Query string `json:"query"`
+
+	// Auth is specify clickhouse user credentials
+	Auth *ClickHouseBackupApiAuth `json:"auth,omitempty"`
+}
+
+// BackupHookOnError describes backup behavior on hook failure
+type BackupHookOnError string
+
+const (
+	// BackupHookOnErrorFail marks backup as failed
+	BackupHookOnErrorFail BackupHookOnError = "Fail"
+
+	// BackupHookOnErrorContinue ignores hook failure
+	BackupHookOnErrorContinue BackupHookOnError = "Continue"
+)
+
+// BackupHookStage is stage, when hook is executed
+type BackupHookStage string
+
+const (
+	BackupHookStagePre  BackupHookStage = "Pre"
+	BackupHookStagePost BackupHookStage = "Post"
+)
+
+// BackupHookStatus is executed hook outcome
+type BackupHookStatus struct {
+	// Name is hook name
+	Name string `json:"name"`
+
+	// Stage is hook execution stage
+	Stage BackupHookStage `json:"stage"`
+
+	// Phase is Succeeded or Failed
+	Phase string `json:"phase"`
+
+	// Message is hook output or error message
+	Message string `json:"message,omitempty"`
+
+	// StartTime is time, when hook execution started
+	StartTime metav1.Time `json:"startTime"`
+
+	// Duration is hook execution duration
+	Duration string `json:"duration,omitempty"`
+}
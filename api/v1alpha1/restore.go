@@ -0,0 +1,20 @@
+package v1alpha1
+
+// BackupReference is reference to backup object restored by restore object
+type BackupReference struct {
+	// Name is backup object name
+	Name string `json:"name"`
+
+	// Namespace is backup object namespace, restore object namespace is used if empty.
+	// Backup in other namespace must allow restore namespace by backups.sputnik.systems/restore-namespaces annotation.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// GetNamespace returns backup object namespace, given restore object namespace is used by default
+func (r BackupReference) GetNamespace(ns string) string {
+	if r.Namespace == "" {
+		return ns
+	}
+
+	return r.Namespace
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RestoreScheduleSourceClickHouse and RestoreScheduleSourceDgraph are supported restore schedule source kinds
+	RestoreScheduleSourceClickHouse = "ClickHouseBackupSchedule"
+	RestoreScheduleSourceDgraph     = "DgraphBackupSchedule"
+)
+
+// RestoreScheduleSpec defines the desired state of RestoreSchedule
+type RestoreScheduleSpec struct {
+	// Schedule is schedule info in github.com/robfig/cron supported notation
+	Schedule string `json:"schedule"`
+
+	// TimeZone is IANA time zone name of schedule, pod local time zone is used if empty
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Suspend is stop scheduled restore objects creation
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Source is backup schedule, which newest completed backup is restored
+	Source RestoreScheduleSource `json:"source"`
+
+	// HistoryLimit is count of kept restore objects created by schedule
+	//+kubebuilder:default=3
+	//+kubebuilder:validation:Minimum=1
+	HistoryLimit int `json:"historyLimit,omitempty"`
+
+	// ClickHouse is specify clickhouse restore options, it is required for ClickHouseBackupSchedule source.
+	// Backup field is set by schedule.
+	ClickHouse *ClickHouseRestoreSpec `json:"clickhouse,omitempty"`
+
+	// Dgraph is specify dgraph restore options, it is required for DgraphBackupSchedule source.
+	// Backup field is set by schedule.
+	Dgraph *DgraphRestoreSpec `json:"dgraph,omitempty"`
+}
+
+// RestoreScheduleSource is reference to backup schedule, which backups are restored
+type RestoreScheduleSource struct {
+	// Kind is backup schedule kind
+	//+kubebuilder:validation:Enum=ClickHouseBackupSchedule;DgraphBackupSchedule
+	Kind string `json:"kind"`
+
+	// Name is backup schedule name
+	Name string `json:"name"`
+
+	// Namespace is backup schedule namespace, restore schedule namespace is used if empty.
+	// Backups in other namespace must allow restore namespace by backups.sputnik.systems/restore-namespaces annotation.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Selector is filter of restored schedule backups by labels
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// GetNamespace returns backup schedule namespace, given restore schedule namespace is used by default
+func (s RestoreScheduleSource) GetNamespace(ns string) string {
+	if s.Namespace == "" {
+		return ns
+	}
+
+	return s.Namespace
+}
+
+// RestoreRun is info about restore object created by restore schedule
+type RestoreRun struct {
+	// Name is restore object name
+	Name string `json:"name"`
+
+	// Backup is restored backup object name
+	Backup string `json:"backup"`
+
+	// Phase is restore object phase
+	Phase string `json:"phase,omitempty"`
+
+	// StartTime is restore object creation time
+	StartTime metav1.Time `json:"startTime"`
+
+	// CompletionTime is time, when restore moved to completed phase
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// RestoreScheduleStatus defines the observed state of RestoreSchedule
+type RestoreScheduleStatus struct {
+	ScheduleTaskID   int         `json:"scheduleTaskId,omitempty"`
+	ActiveGeneration int64       `json:"activeGeneration,omitempty"`
+	UpdatedAt        metav1.Time `json:"updatedTime,omitempty"`
+
+	// LastScheduleTime is creation time of newest restore object
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// LastSuccessfulTime is completion time of newest completed restore
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// LastRestoredBackup is backup object name restored by newest restore object
+	LastRestoredBackup string `json:"lastRestoredBackup,omitempty"`
+
+	// Error is reason of last skipped refresh, e.g. there is no completed backup
+	Error string `json:"error,omitempty"`
+
+	// History is list of kept restore objects, newest first
+	History []RestoreRun `json:"history,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule",description="restore schedule"
+//+kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.source.name",description="restored backup schedule"
+//+kubebuilder:printcolumn:name="Last Backup",type="string",JSONPath=".status.lastRestoredBackup",description="newest restored backup object"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// RestoreSchedule is the Schema for the restoreschedules API
+type RestoreSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RestoreScheduleSpec   `json:"spec,omitempty"`
+	Status RestoreScheduleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RestoreScheduleList contains a list of RestoreSchedule
+type RestoreScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RestoreSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RestoreSchedule{}, &RestoreScheduleList{})
+}
+
+// AsOwner returns owner references with current object as owner
+func (cr *RestoreSchedule) AsOwner() []metav1.OwnerReference {
+	return []metav1.OwnerReference{*metav1.NewControllerRef(cr, GroupVersion.WithKind("RestoreSchedule"))}
+}
+
+// IsNeedUpdate returns true if resource must be updated
+func (cr *RestoreSchedule) IsNeedUpdate(startedAt *metav1.Time) bool {
+	if cr.Generation != cr.Status.ActiveGeneration {
+		return true
+	}
+
+	if cr.Status.UpdatedAt.Before(startedAt) {
+		return true
+	}
+
+	return false
+}
@@ -21,30 +21,1233 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupAuditSpec) DeepCopyInto(out *BackupAuditSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupAuditSpec.
+func (in *BackupAuditSpec) DeepCopy() *BackupAuditSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupAuditSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupAuditStatus) DeepCopyInto(out *BackupAuditStatus) {
+	*out = *in
+	in.LastAuditTime.DeepCopyInto(&out.LastAuditTime)
+	if in.Orphans != nil {
+		in, out := &in.Orphans, &out.Orphans
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupAuditStatus.
+func (in *BackupAuditStatus) DeepCopy() *BackupAuditStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupAuditStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupHook) DeepCopyInto(out *BackupHook) {
+	*out = *in
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(BackupHookExec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(BackupHookHTTP)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SQL != nil {
+		in, out := &in.SQL, &out.SQL
+		*out = new(BackupHookSQL)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHook.
+func (in *BackupHook) DeepCopy() *BackupHook {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupHookExec) DeepCopyInto(out *BackupHookExec) {
+	*out = *in
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHookExec.
+func (in *BackupHookExec) DeepCopy() *BackupHookExec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHookExec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupHookHTTP) DeepCopyInto(out *BackupHookHTTP) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHookHTTP.
+func (in *BackupHookHTTP) DeepCopy() *BackupHookHTTP {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHookHTTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupHookSQL) DeepCopyInto(out *BackupHookSQL) {
+	*out = *in
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(ClickHouseBackupApiAuth)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHookSQL.
+func (in *BackupHookSQL) DeepCopy() *BackupHookSQL {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHookSQL)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupHookStatus) DeepCopyInto(out *BackupHookStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHookStatus.
+func (in *BackupHookStatus) DeepCopy() *BackupHookStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHookStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupHooks) DeepCopyInto(out *BackupHooks) {
+	*out = *in
+	if in.Pre != nil {
+		in, out := &in.Pre, &out.Pre
+		*out = make([]BackupHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Post != nil {
+		in, out := &in.Post, &out.Post
+		*out = make([]BackupHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHooks.
+func (in *BackupHooks) DeepCopy() *BackupHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupNotification) DeepCopyInto(out *BackupNotification) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupNotification.
+func (in *BackupNotification) DeepCopy() *BackupNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BackupNotification) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupNotificationList) DeepCopyInto(out *BackupNotificationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BackupNotification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupNotificationList.
+func (in *BackupNotificationList) DeepCopy() *BackupNotificationList {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupNotificationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BackupNotificationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupNotificationSMTP) DeepCopyInto(out *BackupNotificationSMTP) {
+	*out = *in
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupNotificationSMTP.
+func (in *BackupNotificationSMTP) DeepCopy() *BackupNotificationSMTP {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupNotificationSMTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupNotificationSlack) DeepCopyInto(out *BackupNotificationSlack) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupNotificationSlack.
+func (in *BackupNotificationSlack) DeepCopy() *BackupNotificationSlack {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupNotificationSlack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupNotificationSpec) DeepCopyInto(out *BackupNotificationSpec) {
+	*out = *in
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Engines != nil {
+		in, out := &in.Engines, &out.Engines
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(BackupNotificationWebhook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(BackupNotificationSlack)
+		**out = **in
+	}
+	if in.SMTP != nil {
+		in, out := &in.SMTP, &out.SMTP
+		*out = new(BackupNotificationSMTP)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupNotificationSpec.
+func (in *BackupNotificationSpec) DeepCopy() *BackupNotificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupNotificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupNotificationStatus) DeepCopyInto(out *BackupNotificationStatus) {
+	*out = *in
+	in.LastSentTime.DeepCopyInto(&out.LastSentTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupNotificationStatus.
+func (in *BackupNotificationStatus) DeepCopy() *BackupNotificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupNotificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupNotificationWebhook) DeepCopyInto(out *BackupNotificationWebhook) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupNotificationWebhook.
+func (in *BackupNotificationWebhook) DeepCopy() *BackupNotificationWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupNotificationWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupPolicy) DeepCopyInto(out *BackupPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupPolicy.
+func (in *BackupPolicy) DeepCopy() *BackupPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BackupPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupPolicyList) DeepCopyInto(out *BackupPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BackupPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupPolicyList.
+func (in *BackupPolicyList) DeepCopy() *BackupPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BackupPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupPolicySpec) DeepCopyInto(out *BackupPolicySpec) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClickHouse != nil {
+		in, out := &in.ClickHouse, &out.ClickHouse
+		*out = new(ClickHouseBackupScheduleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dgraph != nil {
+		in, out := &in.Dgraph, &out.Dgraph
+		*out = new(DgraphBackupScheduleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupPolicySpec.
+func (in *BackupPolicySpec) DeepCopy() *BackupPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupPolicyStatus) DeepCopyInto(out *BackupPolicyStatus) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastSyncTime.DeepCopyInto(&out.LastSyncTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupPolicyStatus.
+func (in *BackupPolicyStatus) DeepCopy() *BackupPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupReference) DeepCopyInto(out *BackupReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupReference.
+func (in *BackupReference) DeepCopy() *BackupReference {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupRun) DeepCopyInto(out *BackupRun) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupRun.
+func (in *BackupRun) DeepCopy() *BackupRun {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupScheduleHistory) DeepCopyInto(out *BackupScheduleHistory) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RecentRuns != nil {
+		in, out := &in.RecentRuns, &out.RecentRuns
+		*out = make([]BackupRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupScheduleHistory.
+func (in *BackupScheduleHistory) DeepCopy() *BackupScheduleHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupScheduleHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupSkipStatus) DeepCopyInto(out *BackupSkipStatus) {
+	*out = *in
+	in.LastSkippedTime.DeepCopyInto(&out.LastSkippedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupSkipStatus.
+func (in *BackupSkipStatus) DeepCopy() *BackupSkipStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupSkipStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupTemplate) DeepCopyInto(out *BackupTemplate) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupTemplate.
+func (in *BackupTemplate) DeepCopy() *BackupTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupTemplateMetadata) DeepCopyInto(out *BackupTemplateMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupTemplateMetadata.
+func (in *BackupTemplateMetadata) DeepCopy() *BackupTemplateMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupTemplateMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlackoutWindow) DeepCopyInto(out *BlackoutWindow) {
+	*out = *in
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		*out = (*in).DeepCopy()
+	}
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlackoutWindow.
+func (in *BlackoutWindow) DeepCopy() *BlackoutWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(BlackoutWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClickHouseBackup) DeepCopyInto(out *ClickHouseBackup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackup.
+func (in *ClickHouseBackup) DeepCopy() *ClickHouseBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseBackupApiAuth) DeepCopyInto(out *ClickHouseBackupApiAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupApiAuth.
+func (in *ClickHouseBackupApiAuth) DeepCopy() *ClickHouseBackupApiAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseBackupApiAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseBackupApiTLS) DeepCopyInto(out *ClickHouseBackupApiTLS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupApiTLS.
+func (in *ClickHouseBackupApiTLS) DeepCopy() *ClickHouseBackupApiTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseBackupApiTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseBackupImport) DeepCopyInto(out *ClickHouseBackupImport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupImport.
+func (in *ClickHouseBackupImport) DeepCopy() *ClickHouseBackupImport {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseBackupImport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseBackupImport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseBackupImportList) DeepCopyInto(out *ClickHouseBackupImportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClickHouseBackupImport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupImportList.
+func (in *ClickHouseBackupImportList) DeepCopy() *ClickHouseBackupImportList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseBackupImportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseBackupImportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseBackupImportSpec) DeepCopyInto(out *ClickHouseBackupImportSpec) {
+	*out = *in
+	in.Backup.DeepCopyInto(&out.Backup)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupImportSpec.
+func (in *ClickHouseBackupImportSpec) DeepCopy() *ClickHouseBackupImportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseBackupImportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseBackupImportStatus) DeepCopyInto(out *ClickHouseBackupImportStatus) {
+	*out = *in
+	in.LastScanTime.DeepCopyInto(&out.LastScanTime)
+	if in.ImportedBackups != nil {
+		in, out := &in.ImportedBackups, &out.ImportedBackups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupImportStatus.
+func (in *ClickHouseBackupImportStatus) DeepCopy() *ClickHouseBackupImportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseBackupImportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseBackupList) DeepCopyInto(out *ClickHouseBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClickHouseBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupList.
+func (in *ClickHouseBackupList) DeepCopy() *ClickHouseBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseBackupSchedule) DeepCopyInto(out *ClickHouseBackupSchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupSchedule.
+func (in *ClickHouseBackupSchedule) DeepCopy() *ClickHouseBackupSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseBackupSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseBackupSchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseBackupScheduleList) DeepCopyInto(out *ClickHouseBackupScheduleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClickHouseBackupSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupScheduleList.
+func (in *ClickHouseBackupScheduleList) DeepCopy() *ClickHouseBackupScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseBackupScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseBackupScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseBackupScheduleSpec) DeepCopyInto(out *ClickHouseBackupScheduleSpec) {
+	*out = *in
+	if in.BlackoutWindows != nil {
+		in, out := &in.BlackoutWindows, &out.BlackoutWindows
+		*out = make([]BlackoutWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(BackupAuditSpec)
+		**out = **in
+	}
+	if in.BackupTemplate != nil {
+		in, out := &in.BackupTemplate, &out.BackupTemplate
+		*out = new(BackupTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Backup.DeepCopyInto(&out.Backup)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupScheduleSpec.
+func (in *ClickHouseBackupScheduleSpec) DeepCopy() *ClickHouseBackupScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseBackupScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseBackupScheduleStatus) DeepCopyInto(out *ClickHouseBackupScheduleStatus) {
+	*out = *in
+	in.UpdatedAt.DeepCopyInto(&out.UpdatedAt)
+	in.BackupScheduleHistory.DeepCopyInto(&out.BackupScheduleHistory)
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(BackupAuditStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteBackups != nil {
+		in, out := &in.RemoteBackups, &out.RemoteBackups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Skipped != nil {
+		in, out := &in.Skipped, &out.Skipped
+		*out = new(BackupSkipStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastCompletedTime != nil {
+		in, out := &in.LastCompletedTime, &out.LastCompletedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupScheduleStatus.
+func (in *ClickHouseBackupScheduleStatus) DeepCopy() *ClickHouseBackupScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseBackupScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseBackupSpec) DeepCopyInto(out *ClickHouseBackupSpec) {
+	*out = *in
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(ClickHouseBackupApiAuth)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ClickHouseBackupApiTLS)
+		**out = **in
+	}
+	if in.ExponentialBackOff != nil {
+		in, out := &in.ExponentialBackOff, &out.ExponentialBackOff
+		*out = new(ExponentialBackOffSpec)
+		**out = **in
+	}
+	if in.CreateParams != nil {
+		in, out := &in.CreateParams, &out.CreateParams
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UploadParams != nil {
+		in, out := &in.UploadParams, &out.UploadParams
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(BackupHooks)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupSpec.
+func (in *ClickHouseBackupSpec) DeepCopy() *ClickHouseBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseBackupStatus) DeepCopyInto(out *ClickHouseBackupStatus) {
+	*out = *in
+	out.Api = in.Api
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]BackupHookStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupStatus.
+func (in *ClickHouseBackupStatus) DeepCopy() *ClickHouseBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseBackupStatusApi) DeepCopyInto(out *ClickHouseBackupStatusApi) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupStatusApi.
+func (in *ClickHouseBackupStatusApi) DeepCopy() *ClickHouseBackupStatusApi {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseBackupStatusApi)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseRestore) DeepCopyInto(out *ClickHouseRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseRestore.
+func (in *ClickHouseRestore) DeepCopy() *ClickHouseRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseRestoreList) DeepCopyInto(out *ClickHouseRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClickHouseRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseRestoreList.
+func (in *ClickHouseRestoreList) DeepCopy() *ClickHouseRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClickHouseRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseRestoreSpec) DeepCopyInto(out *ClickHouseRestoreSpec) {
+	*out = *in
+	out.Backup = in.Backup
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(ClickHouseRestoreTarget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseMapping != nil {
+		in, out := &in.DatabaseMapping, &out.DatabaseMapping
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TableMapping != nil {
+		in, out := &in.TableMapping, &out.TableMapping
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DownloadParams != nil {
+		in, out := &in.DownloadParams, &out.DownloadParams
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RestoreParams != nil {
+		in, out := &in.RestoreParams, &out.RestoreParams
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExponentialBackOff != nil {
+		in, out := &in.ExponentialBackOff, &out.ExponentialBackOff
+		*out = new(ExponentialBackOffSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseRestoreSpec.
+func (in *ClickHouseRestoreSpec) DeepCopy() *ClickHouseRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseRestoreStatus) DeepCopyInto(out *ClickHouseRestoreStatus) {
+	*out = *in
+	out.Api = in.Api
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseRestoreStatus.
+func (in *ClickHouseRestoreStatus) DeepCopy() *ClickHouseRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseRestoreTarget) DeepCopyInto(out *ClickHouseRestoreTarget) {
+	*out = *in
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(ClickHouseBackupApiAuth)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ClickHouseBackupApiTLS)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseRestoreTarget.
+func (in *ClickHouseRestoreTarget) DeepCopy() *ClickHouseRestoreTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseRestoreTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterBackupSchedule) DeepCopyInto(out *ClusterBackupSchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackup.
-func (in *ClickHouseBackup) DeepCopy() *ClickHouseBackup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBackupSchedule.
+func (in *ClusterBackupSchedule) DeepCopy() *ClusterBackupSchedule {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseBackup)
+	out := new(ClusterBackupSchedule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClickHouseBackup) DeepCopyObject() runtime.Object {
+func (in *ClusterBackupSchedule) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -52,31 +1255,237 @@ func (in *ClickHouseBackup) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseBackupList) DeepCopyInto(out *ClickHouseBackupList) {
+func (in *ClusterBackupScheduleList) DeepCopyInto(out *ClusterBackupScheduleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterBackupSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBackupScheduleList.
+func (in *ClusterBackupScheduleList) DeepCopy() *ClusterBackupScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterBackupScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterBackupScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterBackupScheduleSpec) DeepCopyInto(out *ClusterBackupScheduleSpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClickHouse != nil {
+		in, out := &in.ClickHouse, &out.ClickHouse
+		*out = new(ClickHouseBackupScheduleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dgraph != nil {
+		in, out := &in.Dgraph, &out.Dgraph
+		*out = new(DgraphBackupScheduleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBackupScheduleSpec.
+func (in *ClusterBackupScheduleSpec) DeepCopy() *ClusterBackupScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterBackupScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterBackupScheduleStatus) DeepCopyInto(out *ClusterBackupScheduleStatus) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastSyncTime.DeepCopyInto(&out.LastSyncTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBackupScheduleStatus.
+func (in *ClusterBackupScheduleStatus) DeepCopy() *ClusterBackupScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterBackupScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DgraphBackup) DeepCopyInto(out *DgraphBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackup.
+func (in *DgraphBackup) DeepCopy() *DgraphBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(DgraphBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DgraphBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DgraphBackupImport) DeepCopyInto(out *DgraphBackupImport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupImport.
+func (in *DgraphBackupImport) DeepCopy() *DgraphBackupImport {
+	if in == nil {
+		return nil
+	}
+	out := new(DgraphBackupImport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DgraphBackupImport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DgraphBackupImportList) DeepCopyInto(out *DgraphBackupImportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DgraphBackupImport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupImportList.
+func (in *DgraphBackupImportList) DeepCopy() *DgraphBackupImportList {
+	if in == nil {
+		return nil
+	}
+	out := new(DgraphBackupImportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DgraphBackupImportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DgraphBackupImportSpec) DeepCopyInto(out *DgraphBackupImportSpec) {
+	*out = *in
+	in.Backup.DeepCopyInto(&out.Backup)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupImportSpec.
+func (in *DgraphBackupImportSpec) DeepCopy() *DgraphBackupImportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DgraphBackupImportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DgraphBackupImportStatus) DeepCopyInto(out *DgraphBackupImportStatus) {
+	*out = *in
+	in.LastScanTime.DeepCopyInto(&out.LastScanTime)
+	if in.ImportedBackups != nil {
+		in, out := &in.ImportedBackups, &out.ImportedBackups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupImportStatus.
+func (in *DgraphBackupImportStatus) DeepCopy() *DgraphBackupImportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DgraphBackupImportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DgraphBackupList) DeepCopyInto(out *DgraphBackupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClickHouseBackup, len(*in))
+		*out = make([]DgraphBackup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupList.
-func (in *ClickHouseBackupList) DeepCopy() *ClickHouseBackupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupList.
+func (in *DgraphBackupList) DeepCopy() *DgraphBackupList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseBackupList)
+	out := new(DgraphBackupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClickHouseBackupList) DeepCopyObject() runtime.Object {
+func (in *DgraphBackupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -84,7 +1493,7 @@ func (in *ClickHouseBackupList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseBackupSchedule) DeepCopyInto(out *ClickHouseBackupSchedule) {
+func (in *DgraphBackupSchedule) DeepCopyInto(out *DgraphBackupSchedule) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -92,18 +1501,18 @@ func (in *ClickHouseBackupSchedule) DeepCopyInto(out *ClickHouseBackupSchedule)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupSchedule.
-func (in *ClickHouseBackupSchedule) DeepCopy() *ClickHouseBackupSchedule {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupSchedule.
+func (in *DgraphBackupSchedule) DeepCopy() *DgraphBackupSchedule {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseBackupSchedule)
+	out := new(DgraphBackupSchedule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClickHouseBackupSchedule) DeepCopyObject() runtime.Object {
+func (in *DgraphBackupSchedule) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -111,31 +1520,31 @@ func (in *ClickHouseBackupSchedule) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseBackupScheduleList) DeepCopyInto(out *ClickHouseBackupScheduleList) {
+func (in *DgraphBackupScheduleList) DeepCopyInto(out *DgraphBackupScheduleList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClickHouseBackupSchedule, len(*in))
+		*out = make([]DgraphBackupSchedule, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupScheduleList.
-func (in *ClickHouseBackupScheduleList) DeepCopy() *ClickHouseBackupScheduleList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupScheduleList.
+func (in *DgraphBackupScheduleList) DeepCopy() *DgraphBackupScheduleList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseBackupScheduleList)
+	out := new(DgraphBackupScheduleList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClickHouseBackupScheduleList) DeepCopyObject() runtime.Object {
+func (in *DgraphBackupScheduleList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -143,104 +1552,167 @@ func (in *ClickHouseBackupScheduleList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseBackupScheduleSpec) DeepCopyInto(out *ClickHouseBackupScheduleSpec) {
+func (in *DgraphBackupScheduleSpec) DeepCopyInto(out *DgraphBackupScheduleSpec) {
 	*out = *in
+	if in.BlackoutWindows != nil {
+		in, out := &in.BlackoutWindows, &out.BlackoutWindows
+		*out = make([]BlackoutWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(BackupAuditSpec)
+		**out = **in
+	}
+	if in.BackupTemplate != nil {
+		in, out := &in.BackupTemplate, &out.BackupTemplate
+		*out = new(BackupTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Backup.DeepCopyInto(&out.Backup)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupScheduleSpec.
-func (in *ClickHouseBackupScheduleSpec) DeepCopy() *ClickHouseBackupScheduleSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupScheduleSpec.
+func (in *DgraphBackupScheduleSpec) DeepCopy() *DgraphBackupScheduleSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseBackupScheduleSpec)
+	out := new(DgraphBackupScheduleSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseBackupScheduleStatus) DeepCopyInto(out *ClickHouseBackupScheduleStatus) {
+func (in *DgraphBackupScheduleStatus) DeepCopyInto(out *DgraphBackupScheduleStatus) {
 	*out = *in
 	in.UpdatedAt.DeepCopyInto(&out.UpdatedAt)
+	in.BackupScheduleHistory.DeepCopyInto(&out.BackupScheduleHistory)
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(BackupAuditStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteBackups != nil {
+		in, out := &in.RemoteBackups, &out.RemoteBackups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Skipped != nil {
+		in, out := &in.Skipped, &out.Skipped
+		*out = new(BackupSkipStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastCompletedTime != nil {
+		in, out := &in.LastCompletedTime, &out.LastCompletedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupScheduleStatus.
-func (in *ClickHouseBackupScheduleStatus) DeepCopy() *ClickHouseBackupScheduleStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupScheduleStatus.
+func (in *DgraphBackupScheduleStatus) DeepCopy() *DgraphBackupScheduleStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseBackupScheduleStatus)
+	out := new(DgraphBackupScheduleStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseBackupSpec) DeepCopyInto(out *ClickHouseBackupSpec) {
+func (in *DgraphBackupSpec) DeepCopyInto(out *DgraphBackupSpec) {
 	*out = *in
-	if in.ExponentialBackOff != nil {
-		in, out := &in.ExponentialBackOff, &out.ExponentialBackOff
-		*out = new(ExponentialBackOffSpec)
-		**out = **in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.CreateParams != nil {
-		in, out := &in.CreateParams, &out.CreateParams
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(StorageCredentials)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.UploadParams != nil {
-		in, out := &in.UploadParams, &out.UploadParams
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(BackupHooks)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupSpec.
-func (in *ClickHouseBackupSpec) DeepCopy() *ClickHouseBackupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupSpec.
+func (in *DgraphBackupSpec) DeepCopy() *DgraphBackupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseBackupSpec)
+	out := new(DgraphBackupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseBackupStatus) DeepCopyInto(out *ClickHouseBackupStatus) {
+func (in *DgraphBackupStatus) DeepCopyInto(out *DgraphBackupStatus) {
 	*out = *in
-	out.Api = in.Api
+	in.ExportResponse.DeepCopyInto(&out.ExportResponse)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]BackupHookStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupStatus.
-func (in *ClickHouseBackupStatus) DeepCopy() *ClickHouseBackupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupStatus.
+func (in *DgraphBackupStatus) DeepCopy() *DgraphBackupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseBackupStatus)
+	out := new(DgraphBackupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClickHouseBackupStatusApi) DeepCopyInto(out *ClickHouseBackupStatusApi) {
+func (in *DgraphBackupStatusExportResonse) DeepCopyInto(out *DgraphBackupStatusExportResonse) {
 	*out = *in
+	if in.ExportedFiles != nil {
+		in, out := &in.ExportedFiles, &out.ExportedFiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupStatusApi.
-func (in *ClickHouseBackupStatusApi) DeepCopy() *ClickHouseBackupStatusApi {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupStatusExportResonse.
+func (in *DgraphBackupStatusExportResonse) DeepCopy() *DgraphBackupStatusExportResonse {
 	if in == nil {
 		return nil
 	}
-	out := new(ClickHouseBackupStatusApi)
+	out := new(DgraphBackupStatusExportResonse)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DgraphBackup) DeepCopyInto(out *DgraphBackup) {
+func (in *DgraphRestore) DeepCopyInto(out *DgraphRestore) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -248,18 +1720,18 @@ func (in *DgraphBackup) DeepCopyInto(out *DgraphBackup) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackup.
-func (in *DgraphBackup) DeepCopy() *DgraphBackup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphRestore.
+func (in *DgraphRestore) DeepCopy() *DgraphRestore {
 	if in == nil {
 		return nil
 	}
-	out := new(DgraphBackup)
+	out := new(DgraphRestore)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DgraphBackup) DeepCopyObject() runtime.Object {
+func (in *DgraphRestore) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -267,31 +1739,31 @@ func (in *DgraphBackup) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DgraphBackupList) DeepCopyInto(out *DgraphBackupList) {
+func (in *DgraphRestoreList) DeepCopyInto(out *DgraphRestoreList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DgraphBackup, len(*in))
+		*out = make([]DgraphRestore, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupList.
-func (in *DgraphBackupList) DeepCopy() *DgraphBackupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphRestoreList.
+func (in *DgraphRestoreList) DeepCopy() *DgraphRestoreList {
 	if in == nil {
 		return nil
 	}
-	out := new(DgraphBackupList)
+	out := new(DgraphRestoreList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DgraphBackupList) DeepCopyObject() runtime.Object {
+func (in *DgraphRestoreList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -299,7 +1771,82 @@ func (in *DgraphBackupList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DgraphBackupSchedule) DeepCopyInto(out *DgraphBackupSchedule) {
+func (in *DgraphRestoreSpec) DeepCopyInto(out *DgraphRestoreSpec) {
+	*out = *in
+	out.Backup = in.Backup
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(StorageCredentials)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphRestoreSpec.
+func (in *DgraphRestoreSpec) DeepCopy() *DgraphRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DgraphRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DgraphRestoreStatus) DeepCopyInto(out *DgraphRestoreStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphRestoreStatus.
+func (in *DgraphRestoreStatus) DeepCopy() *DgraphRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DgraphRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExponentialBackOffSpec) DeepCopyInto(out *ExponentialBackOffSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExponentialBackOffSpec.
+func (in *ExponentialBackOffSpec) DeepCopy() *ExponentialBackOffSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExponentialBackOffSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreRun) DeepCopyInto(out *RestoreRun) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreRun.
+func (in *RestoreRun) DeepCopy() *RestoreRun {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreSchedule) DeepCopyInto(out *RestoreSchedule) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -307,18 +1854,18 @@ func (in *DgraphBackupSchedule) DeepCopyInto(out *DgraphBackupSchedule) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupSchedule.
-func (in *DgraphBackupSchedule) DeepCopy() *DgraphBackupSchedule {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreSchedule.
+func (in *RestoreSchedule) DeepCopy() *RestoreSchedule {
 	if in == nil {
 		return nil
 	}
-	out := new(DgraphBackupSchedule)
+	out := new(RestoreSchedule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DgraphBackupSchedule) DeepCopyObject() runtime.Object {
+func (in *RestoreSchedule) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -326,31 +1873,31 @@ func (in *DgraphBackupSchedule) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DgraphBackupScheduleList) DeepCopyInto(out *DgraphBackupScheduleList) {
+func (in *RestoreScheduleList) DeepCopyInto(out *RestoreScheduleList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DgraphBackupSchedule, len(*in))
+		*out = make([]RestoreSchedule, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupScheduleList.
-func (in *DgraphBackupScheduleList) DeepCopy() *DgraphBackupScheduleList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreScheduleList.
+func (in *RestoreScheduleList) DeepCopy() *RestoreScheduleList {
 	if in == nil {
 		return nil
 	}
-	out := new(DgraphBackupScheduleList)
+	out := new(RestoreScheduleList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DgraphBackupScheduleList) DeepCopyObject() runtime.Object {
+func (in *RestoreScheduleList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -358,104 +1905,160 @@ func (in *DgraphBackupScheduleList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DgraphBackupScheduleSpec) DeepCopyInto(out *DgraphBackupScheduleSpec) {
+func (in *RestoreScheduleSource) DeepCopyInto(out *RestoreScheduleSource) {
 	*out = *in
-	in.Backup.DeepCopyInto(&out.Backup)
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupScheduleSpec.
-func (in *DgraphBackupScheduleSpec) DeepCopy() *DgraphBackupScheduleSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreScheduleSource.
+func (in *RestoreScheduleSource) DeepCopy() *RestoreScheduleSource {
 	if in == nil {
 		return nil
 	}
-	out := new(DgraphBackupScheduleSpec)
+	out := new(RestoreScheduleSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DgraphBackupScheduleStatus) DeepCopyInto(out *DgraphBackupScheduleStatus) {
+func (in *RestoreScheduleSpec) DeepCopyInto(out *RestoreScheduleSpec) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+	if in.ClickHouse != nil {
+		in, out := &in.ClickHouse, &out.ClickHouse
+		*out = new(ClickHouseRestoreSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dgraph != nil {
+		in, out := &in.Dgraph, &out.Dgraph
+		*out = new(DgraphRestoreSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreScheduleSpec.
+func (in *RestoreScheduleSpec) DeepCopy() *RestoreScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreScheduleStatus) DeepCopyInto(out *RestoreScheduleStatus) {
 	*out = *in
 	in.UpdatedAt.DeepCopyInto(&out.UpdatedAt)
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]RestoreRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupScheduleStatus.
-func (in *DgraphBackupScheduleStatus) DeepCopy() *DgraphBackupScheduleStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreScheduleStatus.
+func (in *RestoreScheduleStatus) DeepCopy() *RestoreScheduleStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DgraphBackupScheduleStatus)
+	out := new(RestoreScheduleStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DgraphBackupSpec) DeepCopyInto(out *DgraphBackupSpec) {
+func (in *StorageCredentials) DeepCopyInto(out *StorageCredentials) {
 	*out = *in
-	if in.Secrets != nil {
-		in, out := &in.Secrets, &out.Secrets
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(StorageCredentialsSecret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(StorageCredentialsFile)
+		**out = **in
+	}
+	if in.WebIdentity != nil {
+		in, out := &in.WebIdentity, &out.WebIdentity
+		*out = new(StorageCredentialsWebIdentity)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupSpec.
-func (in *DgraphBackupSpec) DeepCopy() *DgraphBackupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageCredentials.
+func (in *StorageCredentials) DeepCopy() *StorageCredentials {
 	if in == nil {
 		return nil
 	}
-	out := new(DgraphBackupSpec)
+	out := new(StorageCredentials)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DgraphBackupStatus) DeepCopyInto(out *DgraphBackupStatus) {
+func (in *StorageCredentialsFile) DeepCopyInto(out *StorageCredentialsFile) {
 	*out = *in
-	in.ExportResponse.DeepCopyInto(&out.ExportResponse)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupStatus.
-func (in *DgraphBackupStatus) DeepCopy() *DgraphBackupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageCredentialsFile.
+func (in *StorageCredentialsFile) DeepCopy() *StorageCredentialsFile {
 	if in == nil {
 		return nil
 	}
-	out := new(DgraphBackupStatus)
+	out := new(StorageCredentialsFile)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DgraphBackupStatusExportResonse) DeepCopyInto(out *DgraphBackupStatusExportResonse) {
+func (in *StorageCredentialsSecret) DeepCopyInto(out *StorageCredentialsSecret) {
 	*out = *in
-	if in.ExportedFiles != nil {
-		in, out := &in.ExportedFiles, &out.ExportedFiles
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	in.AccessKey.DeepCopyInto(&out.AccessKey)
+	in.SecretKey.DeepCopyInto(&out.SecretKey)
+	if in.SessionToken != nil {
+		in, out := &in.SessionToken, &out.SessionToken
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DgraphBackupStatusExportResonse.
-func (in *DgraphBackupStatusExportResonse) DeepCopy() *DgraphBackupStatusExportResonse {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageCredentialsSecret.
+func (in *StorageCredentialsSecret) DeepCopy() *StorageCredentialsSecret {
 	if in == nil {
 		return nil
 	}
-	out := new(DgraphBackupStatusExportResonse)
+	out := new(StorageCredentialsSecret)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExponentialBackOffSpec) DeepCopyInto(out *ExponentialBackOffSpec) {
+func (in *StorageCredentialsWebIdentity) DeepCopyInto(out *StorageCredentialsWebIdentity) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExponentialBackOffSpec.
-func (in *ExponentialBackOffSpec) DeepCopy() *ExponentialBackOffSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageCredentialsWebIdentity.
+func (in *StorageCredentialsWebIdentity) DeepCopy() *StorageCredentialsWebIdentity {
 	if in == nil {
 		return nil
 	}
-	out := new(ExponentialBackOffSpec)
+	out := new(StorageCredentialsWebIdentity)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,612 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+)
+
+// triggerTimeout is max time of waiting for triggered backup creation
+const triggerTimeout = time.Minute
+
+func listCommand(fs *flag.FlagSet) command {
+	var selector, schedule string
+	fs.StringVar(&selector, "l", "", "")
+	fs.StringVar(&selector, "selector", "", "")
+	fs.StringVar(&schedule, "schedule", "", "")
+
+	return func(ctx context.Context, c client.WithWatch, opts *options, args []string) error {
+		sel, err := labels.Parse(selector)
+		if err != nil {
+			return fmt.Errorf("failed to parse selector: %w", err)
+		}
+
+		if schedule != "" {
+			req, err := labels.NewRequirement(backupsv1alpha1.ScheduleNameLabel, "=", []string{schedule})
+			if err != nil {
+				return fmt.Errorf("failed to parse schedule: %w", err)
+			}
+
+			sel = sel.Add(*req)
+		}
+
+		backups, err := listBackups(ctx, c, opts, client.MatchingLabelsSelector{Selector: sel})
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		if opts.allNamespaces {
+			fmt.Fprint(w, "NAMESPACE\t")
+		}
+		fmt.Fprintln(w, "ENGINE\tNAME\tSCHEDULE\tPHASE\tSIZE\tAGE")
+
+		for _, b := range backups {
+			if opts.allNamespaces {
+				fmt.Fprintf(w, "%s\t", b.Namespace)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", b.Engine, b.Name, valueOrNone(b.Schedule), valueOrNone(b.Phase), formatSize(b.Size), formatAge(b.Created))
+		}
+
+		return w.Flush()
+	}
+}
+
+func triggerCommand(fs *flag.FlagSet) command {
+	var follow bool
+	fs.BoolVar(&follow, "f", false, "")
+	fs.BoolVar(&follow, "follow", false, "")
+
+	return func(ctx context.Context, c client.WithWatch, opts *options, args []string) error {
+		name, err := requireArg(args, "schedule")
+		if err != nil {
+			return err
+		}
+
+		bs, err := getSchedule(ctx, c, opts, name)
+		if err != nil {
+			return err
+		}
+
+		value := strconv.FormatInt(time.Now().Unix(), 10)
+		patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, backupsv1alpha1.TriggerAnnotation, value)
+		if err := c.Patch(ctx, bs.Object, client.RawPatch(types.MergePatchType, []byte(patch))); err != nil {
+			return fmt.Errorf("failed to set trigger annotation: %w", err)
+		}
+
+		fmt.Printf("%s/%s triggered\n", bs.Kind(), bs.Name)
+
+		if !follow {
+			return nil
+		}
+
+		backup, err := waitTriggeredBackup(ctx, c, opts, name, value)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s/%s created\n", backup.Kind(), backup.Name)
+
+		opts.engine = backup.Engine
+
+		return tailBackup(ctx, c, opts, backup.Name)
+	}
+}
+
+// waitTriggeredBackup returns backup created by schedule for given trigger value
+func waitTriggeredBackup(ctx context.Context, c client.Client, opts *options, schedule, trigger string) (*backupInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, triggerTimeout)
+	defer cancel()
+
+	for {
+		bs, err := getSchedule(ctx, c, opts, schedule)
+		if err != nil {
+			return nil, err
+		}
+
+		if bs.LastTrigger == trigger {
+			opts.engine = bs.Engine
+
+			return getBackup(ctx, c, opts, bs.LastTriggeredBackup)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.New("triggered backup is not created, check operator logs")
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func restoreCommand(fs *flag.FlagSet) command {
+	var name, fromNamespace, target, databaseMapping, alpha, zero string
+	fs.StringVar(&name, "name", "", "")
+	fs.StringVar(&fromNamespace, "from-namespace", "", "")
+	fs.StringVar(&target, "target", "", "")
+	fs.StringVar(&databaseMapping, "database-mapping", "", "")
+	fs.StringVar(&alpha, "alpha", "", "")
+	fs.StringVar(&zero, "zero", "", "")
+
+	return func(ctx context.Context, c client.WithWatch, opts *options, args []string) error {
+		backup, err := requireArg(args, "backup")
+		if err != nil {
+			return err
+		}
+
+		// backup is searched in source namespace, restore object is created in target namespace
+		source := *opts
+		if fromNamespace != "" {
+			source.namespace = fromNamespace
+		}
+
+		b, err := getBackup(ctx, c, &source, backup)
+		if err != nil {
+			return err
+		}
+
+		if name == "" {
+			name = fmt.Sprintf("%s-restore-%d", b.Name, time.Now().Unix())
+		}
+
+		ref := backupsv1alpha1.BackupReference{Name: b.Name}
+		if b.Namespace != opts.namespace {
+			ref.Namespace = b.Namespace
+		}
+
+		var obj client.Object
+		switch b.Engine {
+		case engineClickHouse:
+			r := &backupsv1alpha1.ClickHouseRestore{Spec: backupsv1alpha1.ClickHouseRestoreSpec{Backup: ref}}
+			if target != "" {
+				r.Spec.Target = &backupsv1alpha1.ClickHouseRestoreTarget{ApiAddress: target}
+			}
+
+			if databaseMapping != "" {
+				r.Spec.DatabaseMapping, err = parseMapping(databaseMapping)
+				if err != nil {
+					return err
+				}
+			}
+
+			obj = r
+		case engineDgraph:
+			if alpha == "" || zero == "" {
+				return errors.New("--alpha and --zero flags are required for dgraph backup")
+			}
+
+			obj = &backupsv1alpha1.DgraphRestore{Spec: backupsv1alpha1.DgraphRestoreSpec{Backup: ref, AlphaAddress: alpha, ZeroAddress: zero}}
+		}
+
+		obj.SetName(name)
+		obj.SetNamespace(opts.namespace)
+
+		if err := c.Create(ctx, obj); err != nil {
+			return fmt.Errorf("failed to create restore object: %w", err)
+		}
+
+		fmt.Printf("%srestore/%s created\n", b.Engine, name)
+
+		return nil
+	}
+}
+
+// parseMapping parses src:dst,src2:dst2 mapping
+func parseMapping(value string) (map[string]string, error) {
+	m := make(map[string]string)
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.SplitN(item, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid mapping %q, use src:dst", item)
+		}
+
+		m[parts[0]] = parts[1]
+	}
+
+	return m, nil
+}
+
+func tailCommand(_ *flag.FlagSet) command {
+	return func(ctx context.Context, c client.WithWatch, opts *options, args []string) error {
+		name, err := requireArg(args, "backup")
+		if err != nil {
+			return err
+		}
+
+		return tailBackup(ctx, c, opts, name)
+	}
+}
+
+// tailBackup prints backup phase and hooks changes until backup is finished.
+// Error is returned, if backup is failed.
+func tailBackup(ctx context.Context, c client.WithWatch, opts *options, name string) error {
+	b, err := getBackup(ctx, c, opts, name)
+	if err != nil {
+		return err
+	}
+
+	t := &backupTail{out: os.Stdout}
+	t.print(b)
+
+	for !b.IsFinished() {
+		lo := &client.ListOptions{
+			Namespace:     b.Namespace,
+			FieldSelector: fields.OneTermEqualSelector("metadata.name", b.Name),
+			Raw:           &metav1.ListOptions{ResourceVersion: b.Object.GetResourceVersion()},
+		}
+
+		var list client.ObjectList = &backupsv1alpha1.ClickHouseBackupList{}
+		if b.Engine == engineDgraph {
+			list = &backupsv1alpha1.DgraphBackupList{}
+		}
+
+		w, err := c.Watch(ctx, list, lo)
+		if err != nil {
+			return fmt.Errorf("failed to watch backup: %w", err)
+		}
+
+		b, err = watchBackup(ctx, w, t, b)
+		w.Stop()
+		if err != nil {
+			return err
+		}
+	}
+
+	if b.Phase != phaseCompleted {
+		return fmt.Errorf("backup is %s: %s", b.Phase, b.Error)
+	}
+
+	return nil
+}
+
+// watchBackup handles watch events until backup is finished or watch is closed
+func watchBackup(ctx context.Context, w watch.Interface, t *backupTail, b *backupInfo) (*backupInfo, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return b, ctx.Err()
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return b, nil
+			}
+
+			switch event.Type {
+			case watch.Deleted:
+				return b, errors.New("backup is deleted")
+			case watch.Error:
+				return b, fmt.Errorf("watch failed: %v", event.Object)
+			}
+
+			var info backupInfo
+			switch obj := event.Object.(type) {
+			case *backupsv1alpha1.ClickHouseBackup:
+				info = newClickHouseBackupInfo(obj)
+			case *backupsv1alpha1.DgraphBackup:
+				info = newDgraphBackupInfo(obj)
+			default:
+				continue
+			}
+
+			b = &info
+			t.print(b)
+
+			if b.IsFinished() {
+				return b, nil
+			}
+		}
+	}
+}
+
+// backupTail prints only changed backup state
+type backupTail struct {
+	out   io.Writer
+	phase string
+	hooks int
+}
+
+func (t *backupTail) print(b *backupInfo) {
+	now := time.Now().Format("15:04:05")
+
+	if b.Phase != t.phase {
+		t.phase = b.Phase
+
+		line := fmt.Sprintf("%s  %s/%s %s", now, b.Kind(), b.Name, valueOrNone(b.Phase))
+		if b.Phase == phaseCompleted && b.Size > 0 {
+			line += ", size " + formatSize(b.Size)
+		}
+		if b.Error != "" && isContains(failedPhases, b.Phase) {
+			line += ": " + b.Error
+		}
+
+		fmt.Fprintln(t.out, line)
+	}
+
+	for ; t.hooks < len(b.Hooks); t.hooks++ {
+		h := b.Hooks[t.hooks]
+		fmt.Fprintf(t.out, "%s  %s hook %s %s in %s: %s\n", now, strings.ToLower(string(h.Stage)), h.Name, h.Phase, h.Duration, h.Message)
+	}
+}
+
+func treeCommand(_ *flag.FlagSet) command {
+	return func(ctx context.Context, c client.WithWatch, opts *options, args []string) error {
+		if len(args) > 1 {
+			return errors.New("only one schedule may be set")
+		}
+
+		schedules, err := listSchedules(ctx, c, opts)
+		if err != nil {
+			return err
+		}
+
+		backups, err := listBackups(ctx, c, opts)
+		if err != nil {
+			return err
+		}
+
+		// backups are grouped by owner schedule
+		owned := make(map[string][]backupInfo)
+		for _, b := range backups {
+			key := strings.Join([]string{b.Namespace, b.Engine, b.Schedule}, "/")
+			owned[key] = append(owned[key], b)
+		}
+
+		for _, bs := range schedules {
+			if len(args) == 1 && bs.Name != args[0] {
+				continue
+			}
+
+			key := strings.Join([]string{bs.Namespace, bs.Engine, bs.Name}, "/")
+			printTreeSchedule(os.Stdout, opts, &bs, owned[key])
+			delete(owned, key)
+		}
+
+		if len(args) == 1 {
+			return nil
+		}
+
+		// backups without schedule or with removed schedule
+		for _, b := range backups {
+			key := strings.Join([]string{b.Namespace, b.Engine, b.Schedule}, "/")
+			if _, ok := owned[key]; !ok {
+				continue
+			}
+
+			if b.Schedule == "" {
+				fmt.Printf("%s (without schedule)\n", getTreeName(opts, b.Namespace, b.Kind()+"s"))
+			} else {
+				fmt.Printf("%s (not found)\n", getTreeName(opts, b.Namespace, b.Engine+"backupschedule/"+b.Schedule))
+			}
+			printTreeBackups(os.Stdout, owned[key])
+			delete(owned, key)
+		}
+
+		return nil
+	}
+}
+
+func printTreeSchedule(w io.Writer, opts *options, bs *scheduleInfo, backups []backupInfo) {
+	info := bs.Schedule
+	if bs.TimeZone != "" {
+		info += " " + bs.TimeZone
+	}
+	if bs.Retention != "" {
+		info += ", retention " + bs.Retention
+	}
+	if bs.Suspend {
+		info += ", suspended"
+	}
+
+	fmt.Fprintf(w, "%s (%s)\n", getTreeName(opts, bs.Namespace, bs.Kind()+"/"+bs.Name), info)
+	printTreeBackups(w, backups)
+}
+
+func printTreeBackups(w io.Writer, backups []backupInfo) {
+	for i, b := range backups {
+		prefix := "├── "
+		if i == len(backups)-1 {
+			prefix = "└── "
+		}
+
+		fmt.Fprintf(w, "%s%s/%s  %s  %s  %s\n", prefix, b.Kind(), b.Name, valueOrNone(b.Phase), formatSize(b.Size), formatAge(b.Created))
+	}
+}
+
+func getTreeName(opts *options, ns, name string) string {
+	if opts.allNamespaces {
+		return ns + "/" + name
+	}
+
+	return name
+}
+
+func describeCommand(_ *flag.FlagSet) command {
+	return func(ctx context.Context, c client.WithWatch, opts *options, args []string) error {
+		name, err := requireArg(args, "name")
+		if err != nil {
+			return err
+		}
+
+		// kind may be set explicitly as backup/NAME or schedule/NAME
+		kind := ""
+		if parts := strings.SplitN(name, "/", 2); len(parts) == 2 {
+			kind, name = parts[0], parts[1]
+		}
+
+		switch kind {
+		case "backup":
+			b, err := getBackup(ctx, c, opts, name)
+			if err != nil {
+				return err
+			}
+
+			return describeBackup(os.Stdout, b)
+		case "schedule":
+			bs, err := getSchedule(ctx, c, opts, name)
+			if err != nil {
+				return err
+			}
+
+			return describeSchedule(os.Stdout, bs)
+		case "":
+		default:
+			return fmt.Errorf("unknown kind %q, use backup or schedule", kind)
+		}
+
+		b, backupErr := getBackup(ctx, c, opts, name)
+		bs, scheduleErr := getSchedule(ctx, c, opts, name)
+
+		switch {
+		case backupErr == nil && scheduleErr == nil:
+			return fmt.Errorf("both backup and schedule %s exist, use backup/%s or schedule/%s", name, name, name)
+		case backupErr == nil:
+			return describeBackup(os.Stdout, b)
+		case scheduleErr == nil:
+			return describeSchedule(os.Stdout, bs)
+		default:
+			return fmt.Errorf("%v, %v", backupErr, scheduleErr)
+		}
+	}
+}
+
+func describeBackup(out io.Writer, b *backupInfo) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Name:\t%s\n", b.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", b.Namespace)
+	fmt.Fprintf(w, "Engine:\t%s\n", b.Engine)
+	fmt.Fprintf(w, "Schedule:\t%s\n", valueOrNone(b.Schedule))
+	fmt.Fprintf(w, "Target:\t%s\n", b.Target)
+	fmt.Fprintf(w, "Phase:\t%s\n", valueOrNone(b.Phase))
+	if b.Error != "" {
+		fmt.Fprintf(w, "Error:\t%s\n", b.Error)
+	}
+	fmt.Fprintf(w, "Size:\t%s\n", formatSize(b.Size))
+	fmt.Fprintf(w, "Created:\t%s (%s ago)\n", b.Created.Format(time.RFC3339), formatAge(b.Created))
+	if b.CompletionTime != nil {
+		fmt.Fprintf(w, "Completed:\t%s (took %s)\n", b.CompletionTime.Format(time.RFC3339), duration.HumanDuration(b.CompletionTime.Sub(b.Created)))
+	}
+	if backupsv1alpha1.IsImported(b.Object) {
+		fmt.Fprintf(w, "Imported:\ttrue\n")
+	}
+	if b.TraceID != "" {
+		fmt.Fprintf(w, "Trace ID:\t%s\n", b.TraceID)
+	}
+
+	if len(b.Hooks) > 0 {
+		fmt.Fprintln(w, "Hooks:")
+		fmt.Fprintln(w, "  STAGE\tNAME\tPHASE\tDURATION\tMESSAGE")
+		for _, h := range b.Hooks {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", h.Stage, h.Name, h.Phase, h.Duration, firstLine(h.Message))
+		}
+	}
+
+	return w.Flush()
+}
+
+func describeSchedule(out io.Writer, bs *scheduleInfo) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Name:\t%s\n", bs.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", bs.Namespace)
+	fmt.Fprintf(w, "Engine:\t%s\n", bs.Engine)
+	fmt.Fprintf(w, "Schedule:\t%s\n", bs.Schedule)
+	if bs.TimeZone != "" {
+		fmt.Fprintf(w, "Time Zone:\t%s\n", bs.TimeZone)
+	}
+	fmt.Fprintf(w, "Retention:\t%s\n", valueOrNone(bs.Retention))
+	fmt.Fprintf(w, "Suspend:\t%t\n", bs.Suspend)
+	fmt.Fprintf(w, "Last Schedule:\t%s\n", formatTime(bs.History.LastScheduleTime))
+	fmt.Fprintf(w, "Last Success:\t%s\n", formatTime(bs.History.LastSuccessfulTime))
+	fmt.Fprintf(w, "Next Schedule:\t%s\n", formatTime(bs.History.NextScheduleTime))
+	fmt.Fprintf(w, "Active:\t%d\n", bs.History.Active)
+	fmt.Fprintf(w, "Retained:\t%d\n", bs.History.Retained)
+	if bs.LastTrigger != "" {
+		fmt.Fprintf(w, "Last Trigger:\t%s (%s)\n", bs.LastTrigger, bs.LastTriggeredBackup)
+	}
+
+	if len(bs.Conditions) > 0 {
+		fmt.Fprintln(w, "Conditions:")
+		fmt.Fprintln(w, "  TYPE\tSTATUS\tREASON\tMESSAGE")
+		for _, c := range bs.Conditions {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", c.Type, c.Status, c.Reason, c.Message)
+		}
+	}
+
+	if len(bs.History.RecentRuns) > 0 {
+		fmt.Fprintln(w, "Recent Runs:")
+		fmt.Fprintln(w, "  NAME\tPHASE\tSTARTED\tDURATION\tSIZE")
+		for _, run := range bs.History.RecentRuns {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", run.Name, valueOrNone(run.Phase), run.StartTime.Format(time.RFC3339), valueOrNone(run.Duration), formatSize(run.Size))
+		}
+	}
+
+	return w.Flush()
+}
+
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "<none>"
+	}
+
+	return duration.HumanDuration(time.Since(t))
+}
+
+func formatTime(t *metav1.Time) string {
+	if t == nil {
+		return "<none>"
+	}
+
+	return fmt.Sprintf("%s (%s ago)", t.Format(time.RFC3339), formatAge(t.Time))
+}
+
+// formatSize returns size in binary units
+func formatSize(size int64) string {
+	if size <= 0 {
+		return "-"
+	}
+
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+
+	return value
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i] + "..."
+	}
+
+	return s
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-backups is kubectl plugin for backup objects listing, schedules triggering,
+// backups progress tailing and describing
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+)
+
+const usage = `Usage: kubectl backups <command> [flags] [args]
+
+Commands:
+  list                  List backups of all engines with phase, size and age
+  trigger SCHEDULE      Create backup by schedule immediately
+  restore BACKUP        Create restore object from backup, --from-namespace is backup namespace,
+                        --target is clickhouse-backup api address, --database-mapping is src:dst list,
+                        --alpha and --zero are dgraph grpc addresses
+  tail BACKUP           Watch backup status until it is finished
+  tree [SCHEDULE]       Print schedules with their backups
+  describe NAME         Describe backup or schedule
+
+Common flags:
+  -n, --namespace       Namespace, current context namespace by default
+  -A, --all-namespaces  List objects in all namespaces (list and tree only)
+      --engine          Engine: clickhouse or dgraph, all engines by default
+      --kubeconfig      Path to kubeconfig file
+      --context         Kubeconfig context
+`
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = backupsv1alpha1.AddToScheme(scheme)
+}
+
+// options is common command flags
+type options struct {
+	namespace     string
+	allNamespaces bool
+	engine        string
+	kubeconfig    string
+	context       string
+}
+
+// command runs plugin command with given positional arguments
+type command func(ctx context.Context, c client.WithWatch, opts *options, args []string) error
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "--help" || os.Args[1] == "help" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if err := run(ctx, os.Args[1], os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, name string, args []string) error {
+	opts := &options{}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	fs.StringVar(&opts.namespace, "n", "", "")
+	fs.StringVar(&opts.namespace, "namespace", "", "")
+	fs.BoolVar(&opts.allNamespaces, "A", false, "")
+	fs.BoolVar(&opts.allNamespaces, "all-namespaces", false, "")
+	fs.StringVar(&opts.engine, "engine", "", "")
+	fs.StringVar(&opts.kubeconfig, "kubeconfig", "", "")
+	fs.StringVar(&opts.context, "context", "", "")
+
+	var cmd command
+	switch name {
+	case "list":
+		cmd = listCommand(fs)
+	case "trigger":
+		cmd = triggerCommand(fs)
+	case "restore":
+		cmd = restoreCommand(fs)
+	case "tail":
+		cmd = tailCommand(fs)
+	case "tree":
+		cmd = treeCommand(fs)
+	case "describe":
+		cmd = describeCommand(fs)
+	default:
+		return fmt.Errorf("unknown command %q, see kubectl backups --help", name)
+	}
+
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	switch opts.engine {
+	case "", engineClickHouse, engineDgraph:
+	default:
+		return fmt.Errorf("unknown engine %q", opts.engine)
+	}
+
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+
+	return cmd(ctx, c, opts, args)
+}
+
+// parseArgs parses flags placed both before and after positional arguments
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := make([]string, 0)
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// newClient returns client configured by kubeconfig, namespace of current context is used by default
+func newClient(opts *options) (client.WithWatch, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = opts.kubeconfig
+
+	cc := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: opts.context})
+
+	if opts.namespace == "" {
+		ns, _, err := cc.Namespace()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current namespace: %w", err)
+		}
+
+		opts.namespace = ns
+	}
+
+	cfg, err := cc.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	return client.NewWithWatch(cfg, client.Options{Scheme: scheme})
+}
+
+// requireArg returns single positional argument
+func requireArg(args []string, name string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New(name + " argument is required")
+	}
+
+	return args[0], nil
+}
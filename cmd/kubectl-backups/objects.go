@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+)
+
+const (
+	engineClickHouse = "clickhouse"
+	engineDgraph     = "dgraph"
+)
+
+var engines = []string{engineClickHouse, engineDgraph}
+
+const phaseCompleted = "Completed"
+
+// failedPhases is backup phases, which are finished unsuccessfully
+var failedPhases = []string{"Failed", "CreateFailed", "UploadFailed", "Missing"}
+
+// backupInfo is engine independent backup object info
+type backupInfo struct {
+	Engine         string
+	Namespace      string
+	Name           string
+	Schedule       string
+	Target         string
+	Phase          string
+	Error          string
+	Size           int64
+	Created        time.Time
+	CompletionTime *metav1.Time
+	TraceID        string
+	Hooks          []backupsv1alpha1.BackupHookStatus
+	Object         client.Object
+}
+
+// Kind returns backup object kind in lower case
+func (b *backupInfo) Kind() string {
+	return b.Engine + "backup"
+}
+
+// IsFinished returns true, when backup will not be changed by operator anymore
+func (b *backupInfo) IsFinished() bool {
+	return b.Phase == phaseCompleted || isContains(failedPhases, b.Phase)
+}
+
+// scheduleInfo is engine independent backup schedule object info
+type scheduleInfo struct {
+	Engine              string
+	Namespace           string
+	Name                string
+	Schedule            string
+	TimeZone            string
+	Retention           string
+	Suspend             bool
+	LastTrigger         string
+	LastTriggeredBackup string
+	History             backupsv1alpha1.BackupScheduleHistory
+	Conditions          []metav1.Condition
+	Object              client.Object
+}
+
+// Kind returns schedule object kind in lower case
+func (s *scheduleInfo) Kind() string {
+	return s.Engine + "backupschedule"
+}
+
+func newClickHouseBackupInfo(b *backupsv1alpha1.ClickHouseBackup) backupInfo {
+	return backupInfo{
+		Engine:         engineClickHouse,
+		Namespace:      b.Namespace,
+		Name:           b.Name,
+		Schedule:       b.Labels[backupsv1alpha1.ScheduleNameLabel],
+		Target:         b.Spec.ApiAddress,
+		Phase:          b.Status.Phase,
+		Error:          b.Status.Error,
+		Size:           b.Status.Size,
+		Created:        backupsv1alpha1.GetCreationTime(b),
+		CompletionTime: b.Status.CompletionTime,
+		TraceID:        b.Status.TraceID,
+		Hooks:          b.Status.Hooks,
+		Object:         b,
+	}
+}
+
+func newDgraphBackupInfo(b *backupsv1alpha1.DgraphBackup) backupInfo {
+	return backupInfo{
+		Engine:         engineDgraph,
+		Namespace:      b.Namespace,
+		Name:           b.Name,
+		Schedule:       b.Labels[backupsv1alpha1.ScheduleNameLabel],
+		Target:         b.Spec.AdminUrl + " -> " + b.Spec.Destination,
+		Phase:          b.Status.Phase,
+		Error:          b.Status.Error,
+		Size:           b.Status.Size,
+		Created:        backupsv1alpha1.GetCreationTime(b),
+		CompletionTime: b.Status.CompletionTime,
+		TraceID:        b.Status.TraceID,
+		Hooks:          b.Status.Hooks,
+		Object:         b,
+	}
+}
+
+func newClickHouseScheduleInfo(bs *backupsv1alpha1.ClickHouseBackupSchedule) scheduleInfo {
+	return scheduleInfo{
+		Engine:              engineClickHouse,
+		Namespace:           bs.Namespace,
+		Name:                bs.Name,
+		Schedule:            bs.Spec.Schedule,
+		TimeZone:            bs.Spec.TimeZone,
+		Retention:           bs.Spec.Retention,
+		Suspend:             bs.Spec.Suspend,
+		LastTrigger:         bs.Status.LastTrigger,
+		LastTriggeredBackup: bs.Status.LastTriggeredBackup,
+		History:             bs.Status.BackupScheduleHistory,
+		Conditions:          bs.Status.Conditions,
+		Object:              bs,
+	}
+}
+
+func newDgraphScheduleInfo(bs *backupsv1alpha1.DgraphBackupSchedule) scheduleInfo {
+	return scheduleInfo{
+		Engine:              engineDgraph,
+		Namespace:           bs.Namespace,
+		Name:                bs.Name,
+		Schedule:            bs.Spec.Schedule,
+		TimeZone:            bs.Spec.TimeZone,
+		Retention:           bs.Spec.Retention,
+		Suspend:             bs.Spec.Suspend,
+		LastTrigger:         bs.Status.LastTrigger,
+		LastTriggeredBackup: bs.Status.LastTriggeredBackup,
+		History:             bs.Status.BackupScheduleHistory,
+		Conditions:          bs.Status.Conditions,
+		Object:              bs,
+	}
+}
+
+// getEngines returns engines selected by engine flag
+func getEngines(opts *options) []string {
+	if opts.engine != "" {
+		return []string{opts.engine}
+	}
+
+	return engines
+}
+
+func getListOptions(opts *options, extra ...client.ListOption) []client.ListOption {
+	lo := make([]client.ListOption, 0)
+	if !opts.allNamespaces {
+		lo = append(lo, client.InNamespace(opts.namespace))
+	}
+
+	return append(lo, extra...)
+}
+
+// listBackups returns backups of selected engines sorted by namespace and creation time
+func listBackups(ctx context.Context, c client.Client, opts *options, extra ...client.ListOption) ([]backupInfo, error) {
+	out := make([]backupInfo, 0)
+
+	for _, engine := range getEngines(opts) {
+		switch engine {
+		case engineClickHouse:
+			bl := &backupsv1alpha1.ClickHouseBackupList{}
+			if err := c.List(ctx, bl, getListOptions(opts, extra...)...); err != nil {
+				return nil, fmt.Errorf("failed to list clickhouse backups: %w", err)
+			}
+
+			for i := range bl.Items {
+				out = append(out, newClickHouseBackupInfo(&bl.Items[i]))
+			}
+		case engineDgraph:
+			bl := &backupsv1alpha1.DgraphBackupList{}
+			if err := c.List(ctx, bl, getListOptions(opts, extra...)...); err != nil {
+				return nil, fmt.Errorf("failed to list dgraph backups: %w", err)
+			}
+
+			for i := range bl.Items {
+				out = append(out, newDgraphBackupInfo(&bl.Items[i]))
+			}
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+
+		return out[i].Created.Before(out[j].Created)
+	})
+
+	return out, nil
+}
+
+// listSchedules returns schedules of selected engines sorted by namespace and name
+func listSchedules(ctx context.Context, c client.Client, opts *options) ([]scheduleInfo, error) {
+	out := make([]scheduleInfo, 0)
+
+	for _, engine := range getEngines(opts) {
+		switch engine {
+		case engineClickHouse:
+			sl := &backupsv1alpha1.ClickHouseBackupScheduleList{}
+			if err := c.List(ctx, sl, getListOptions(opts)...); err != nil {
+				return nil, fmt.Errorf("failed to list clickhouse backup schedules: %w", err)
+			}
+
+			for i := range sl.Items {
+				out = append(out, newClickHouseScheduleInfo(&sl.Items[i]))
+			}
+		case engineDgraph:
+			sl := &backupsv1alpha1.DgraphBackupScheduleList{}
+			if err := c.List(ctx, sl, getListOptions(opts)...); err != nil {
+				return nil, fmt.Errorf("failed to list dgraph backup schedules: %w", err)
+			}
+
+			for i := range sl.Items {
+				out = append(out, newDgraphScheduleInfo(&sl.Items[i]))
+			}
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+
+		return out[i].Name < out[j].Name
+	})
+
+	return out, nil
+}
+
+// getBackup returns backup with given name, it is searched in all selected engines
+func getBackup(ctx context.Context, c client.Client, opts *options, name string) (*backupInfo, error) {
+	found := make([]backupInfo, 0)
+	key := types.NamespacedName{Namespace: opts.namespace, Name: name}
+
+	for _, engine := range getEngines(opts) {
+		switch engine {
+		case engineClickHouse:
+			b := &backupsv1alpha1.ClickHouseBackup{}
+			if err := c.Get(ctx, key, b); err == nil {
+				found = append(found, newClickHouseBackupInfo(b))
+			} else if client.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("failed to get clickhouse backup: %w", err)
+			}
+		case engineDgraph:
+			b := &backupsv1alpha1.DgraphBackup{}
+			if err := c.Get(ctx, key, b); err == nil {
+				found = append(found, newDgraphBackupInfo(b))
+			} else if client.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("failed to get dgraph backup: %w", err)
+			}
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("backup %s not found", key)
+	case 1:
+		return &found[0], nil
+	default:
+		return nil, fmt.Errorf("backup %s exists for several engines, set --engine flag", key)
+	}
+}
+
+// getSchedule returns schedule with given name, it is searched in all selected engines
+func getSchedule(ctx context.Context, c client.Client, opts *options, name string) (*scheduleInfo, error) {
+	found := make([]scheduleInfo, 0)
+	key := types.NamespacedName{Namespace: opts.namespace, Name: name}
+
+	for _, engine := range getEngines(opts) {
+		switch engine {
+		case engineClickHouse:
+			bs := &backupsv1alpha1.ClickHouseBackupSchedule{}
+			if err := c.Get(ctx, key, bs); err == nil {
+				found = append(found, newClickHouseScheduleInfo(bs))
+			} else if client.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("failed to get clickhouse backup schedule: %w", err)
+			}
+		case engineDgraph:
+			bs := &backupsv1alpha1.DgraphBackupSchedule{}
+			if err := c.Get(ctx, key, bs); err == nil {
+				found = append(found, newDgraphScheduleInfo(bs))
+			} else if client.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("failed to get dgraph backup schedule: %w", err)
+			}
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("backup schedule %s not found", key)
+	case 1:
+		return &found[0], nil
+	default:
+		return nil, fmt.Errorf("backup schedule %s exists for several engines, set --engine flag", key)
+	}
+}
+
+func isContains(src []string, value string) bool {
+	for _, item := range src {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
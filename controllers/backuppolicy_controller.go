@@ -0,0 +1,134 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/controllers/factory"
+	"github.com/sputnik-systems/backups-operator/internal/tracing"
+)
+
+// BackupPolicyReconciler reconciles a BackupPolicy object
+type BackupPolicyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=backuppolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=backuppolicies/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=backuppolicies/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=services;pods;namespaces,verbs=get;list;watch
+
+// Reconcile discovers objects matching backup policy selectors and keeps backup schedules
+// for each of them. Schedules are deleted by garbage collector with backup policy.
+func (r *BackupPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "BackupPolicy.Reconcile", tracing.Object(req.Name, req.Namespace)...)
+	defer func() { tracing.End(span, err) }()
+
+	l := log.FromContext(ctx)
+
+	l.V(1).Info("started resource reconclie")
+
+	bp := &backupsv1alpha1.BackupPolicy{}
+	err = r.Get(ctx, req.NamespacedName, bp)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		l.Error(err, "failed to get backup policy object for reconclie")
+
+		return ctrl.Result{}, err
+	}
+
+	if !bp.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	requeueAfter, err := factory.ProccessBackupPolicyObject(ctx, r.Client, l, bp)
+	if err != nil {
+		l.Error(err, "failed to process backup policy object")
+
+		return ctrl.Result{}, err
+	}
+
+	l.V(1).Info("finished resource reconclie")
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *BackupPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&backupsv1alpha1.BackupPolicy{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&backupsv1alpha1.ClickHouseBackupSchedule{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&backupsv1alpha1.DgraphBackupSchedule{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&source.Kind{Type: &corev1.Namespace{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapTargetToPolicies("")),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.Service{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapTargetToPolicies(backupsv1alpha1.BackupPolicyTargetService)),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.Pod{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapTargetToPolicies(backupsv1alpha1.BackupPolicyTargetPod)),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}),
+		).
+		Complete(r)
+}
+
+// mapTargetToPolicies enqueues backup policies with given target kind, all policies are enqueued if it is empty
+func (r *BackupPolicyReconciler) mapTargetToPolicies(kind string) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		bpl := &backupsv1alpha1.BackupPolicyList{}
+		if err := r.List(context.Background(), bpl); err != nil {
+			log.Log.Error(err, "failed to list backup policy objects")
+
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(bpl.Items))
+		for i := range bpl.Items {
+			bp := &bpl.Items[i]
+
+			if kind == "" || bp.GetTargetKind() == kind {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: bp.Name}})
+			}
+		}
+
+		return requests
+	}
+}
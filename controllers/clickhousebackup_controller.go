@@ -29,17 +29,23 @@ import (
 	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
 	"github.com/sputnik-systems/backups-operator/controllers/factory"
 	"github.com/sputnik-systems/backups-operator/internal/metrics"
+	"github.com/sputnik-systems/backups-operator/internal/notify"
+	"github.com/sputnik-systems/backups-operator/internal/tracing"
 )
 
 // ClickHouseBackupReconciler reconciles a ClickHouseBackup object
 type ClickHouseBackupReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Notifier is used to send backup completion and failure notifications
+	Notifier notify.Notifier
 }
 
 //+kubebuilder:rbac:groups=backups.sputnik.systems,resources=clickhousebackups,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=backups.sputnik.systems,resources=clickhousebackups/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=backups.sputnik.systems,resources=clickhousebackups/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=pods;endpoints,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -50,13 +56,16 @@ type ClickHouseBackupReconciler struct {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.9.2/pkg/reconcile
-func (r *ClickHouseBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *ClickHouseBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "ClickHouseBackup.Reconcile", tracing.Object(req.Name, req.Namespace)...)
+	defer func() { tracing.End(span, err) }()
+
 	l := log.FromContext(ctx)
 
 	l.V(1).Info("started resource reconclie")
 
 	b := &backupsv1alpha1.ClickHouseBackup{}
-	err := r.Get(ctx, req.NamespacedName, b)
+	err = r.Get(ctx, req.NamespacedName, b)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return ctrl.Result{}, nil
@@ -73,18 +82,20 @@ func (r *ClickHouseBackupReconciler) Reconcile(ctx context.Context, req ctrl.Req
 			l.Error(err, "failed to delete clickhouse backup object")
 		}
 
-		metrics.BackupsByController.Delete(
-			prometheus.Labels{
-				"name":       b.Name,
-				"namespace":  b.Namespace,
-				"controller": "clickhousebackup",
-			},
-		)
+		factory.DeleteBackupMetrics(factory.EngineClickHouse, "clickhousebackup", b, b.Status.Phase)
 
 		return ctrl.Result{}, err
 	}
 
-	if err := factory.ProccessClickHouseBackupObject(ctx, r.Client, l, b); err != nil {
+	phase := b.Status.Phase
+
+	err = factory.ProccessClickHouseBackupObject(ctx, r.Client, l, b)
+	// phase transitions are observed by factory, when phase is set
+	if b.Status.Phase != phase {
+		factory.NotifyPhaseTransition(ctx, l, r.Notifier, factory.EngineClickHouse, b, b.Status.Phase, b.Status.Error, b.Status.Size)
+	}
+
+	if err != nil {
 		metrics.BackupsByController.With(
 			prometheus.Labels{
 				"name":       b.Name,
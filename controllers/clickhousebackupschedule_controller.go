@@ -24,9 +24,11 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/robfig/cron/v3"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -34,6 +36,8 @@ import (
 	"github.com/sputnik-systems/backups-operator/controllers/factory"
 	"github.com/sputnik-systems/backups-operator/controllers/factory/finalize"
 	"github.com/sputnik-systems/backups-operator/internal/metrics"
+	"github.com/sputnik-systems/backups-operator/internal/notify"
+	"github.com/sputnik-systems/backups-operator/internal/tracing"
 )
 
 // ClickHouseBackupScheduleReconciler reconciles a ClickHouseBackupSchedule object
@@ -42,11 +46,18 @@ type ClickHouseBackupScheduleReconciler struct {
 	Scheme    *runtime.Scheme
 	Cron      *cron.Cron
 	StartedAt metav1.Time
+
+	// PrometheusRules enables PrometheusRule reconciliation for each schedule
+	PrometheusRules bool
+
+	// Notifier is used to send schedule state change notifications
+	Notifier notify.Notifier
 }
 
 //+kubebuilder:rbac:groups=backups.sputnik.systems,resources=clickhousebackupschedules,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=backups.sputnik.systems,resources=clickhousebackupschedules/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=backups.sputnik.systems,resources=clickhousebackupschedules/finalizers,verbs=update
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -57,13 +68,16 @@ type ClickHouseBackupScheduleReconciler struct {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.9.2/pkg/reconcile
-func (r *ClickHouseBackupScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *ClickHouseBackupScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "ClickHouseBackupSchedule.Reconcile", tracing.Object(req.Name, req.Namespace)...)
+	defer func() { tracing.End(span, err) }()
+
 	l := log.FromContext(ctx)
 
 	l.V(1).Info("started resource reconclie")
 
 	bs := &backupsv1alpha1.ClickHouseBackupSchedule{}
-	err := r.Get(ctx, req.NamespacedName, bs)
+	err = r.Get(ctx, req.NamespacedName, bs)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return ctrl.Result{}, nil
@@ -75,12 +89,11 @@ func (r *ClickHouseBackupScheduleReconciler) Reconcile(ctx context.Context, req
 	}
 
 	if !bs.DeletionTimestamp.IsZero() {
-		id := cron.EntryID(bs.Status.ScheduleTaskID)
-		for _, entry := range r.Cron.Entries() {
-			if entry.ID == id {
-				r.Cron.Remove(id)
-			}
-		}
+		factory.RemoveTask(r.Cron, bs.Status.ScheduleTaskID)
+		factory.RemoveTask(r.Cron, bs.Status.AuditTaskID)
+		factory.RemoveTask(r.Cron, bs.Status.FreshnessTaskID)
+
+		factory.DeleteScheduleMetrics(factory.EngineClickHouse, bs.Name, bs.Namespace, "clickhousebackupschedule")
 
 		if err := finalize.RemoveFinalizeObjByName(ctx, r.Client, bs, bs.Name, bs.Namespace); err != nil {
 			return ctrl.Result{}, err
@@ -96,23 +109,78 @@ func (r *ClickHouseBackupScheduleReconciler) Reconcile(ctx context.Context, req
 			return ctrl.Result{}, err
 		}
 
-		createBackupFunc := func() {
-			l.V(3).Info("executing backup create schedule")
+		createBackupFunc := func(tick time.Time) {
+			l.V(3).Info("executing backup create schedule", "tick", tick)
 
-			name := fmt.Sprintf("%s-%d", bs.Name, time.Now().Unix())
+			reason, err := factory.GetSkipReason(bs.Spec.Suspend, bs.Spec.BlackoutWindows, time.Now())
+			if err != nil {
+				metrics.ScheduledTaskFailuresByControllerTotal.With(
+					prometheus.Labels{
+						"name":       bs.Name,
+						"namespace":  bs.Namespace,
+						"controller": "clickhousebackupschedule",
+						"action":     "create",
+					},
+				).Inc()
 
-			l.V(3).Info("creating backup object", "name", name)
+				l.Error(err, "failed to check blackout windows")
 
-			b := &backupsv1alpha1.ClickHouseBackup{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:            name,
-					Namespace:       bs.Namespace,
-					OwnerReferences: bs.AsOwner(),
-				},
-				Spec: bs.Spec.Backup,
+				return
 			}
 
+			if reason != "" {
+				l.V(3).Info("skipping backup creation", "reason", reason)
+
+				if err := factory.SkipClickHouseScheduleTick(ctx, r.Client, req.NamespacedName, reason); err != nil {
+					l.Error(err, "failed to record skipped schedule tick")
+				}
+
+				return
+			}
+
+			b, err := factory.NewClickHouseScheduleBackup(bs, tick)
+			if err != nil {
+				metrics.ScheduledTaskFailuresByControllerTotal.With(
+					prometheus.Labels{
+						"name":       bs.Name,
+						"namespace":  bs.Namespace,
+						"controller": "clickhousebackupschedule",
+						"action":     "create",
+					},
+				).Inc()
+
+				l.Error(err, "failed to get clickhouse backup object")
+
+				return
+			}
+
+			l.V(3).Info("creating backup object", "name", b.Name)
+
 			if err := r.Create(ctx, b); err != nil {
+				if errors.IsAlreadyExists(err) {
+					conflict, err := factory.IsScheduleBackupNameConflict(ctx, r.Client, b, tick)
+					if err != nil {
+						l.Error(err, "failed to check existing backup object")
+
+						return
+					}
+
+					// backup for this tick is already created by another operator replica
+					if !conflict {
+						l.V(3).Info("backup object already exists", "name", b.Name)
+
+						return
+					}
+
+					l.Info("skipping backup creation, backup object of previous tick has the same name", "name", b.Name)
+
+					if err := factory.SkipClickHouseScheduleTick(ctx, r.Client, req.NamespacedName, factory.SkipReasonNameConflict); err != nil {
+						l.Error(err, "failed to record skipped schedule tick")
+					}
+
+					return
+				}
+
 				metrics.ScheduledTaskFailuresByControllerTotal.With(
 					prometheus.Labels{
 						"name":       bs.Name,
@@ -126,87 +194,190 @@ func (r *ClickHouseBackupScheduleReconciler) Reconcile(ctx context.Context, req
 			}
 		}
 
+		// blackout windows are validated before scheduling creation task, so broken spec is reported on reconcile
+		if _, err := factory.GetSkipReason(false, bs.Spec.BlackoutWindows, time.Now()); err != nil {
+			l.Error(err, "failed to check blackout windows")
+
+			return ctrl.Result{}, err
+		}
+
+		if err := factory.ValidateBackupNameTemplate(bs, bs.Spec.BackupTemplate, bs.Spec.Schedule, bs.Spec.TimeZone, bs.Spec.Jitter); err != nil {
+			l.Error(err, "failed to validate backup name template")
+
+			return ctrl.Result{}, err
+		}
+
 		l.V(2).Info("schedule backup creation task")
 
-		id, err := factory.ScheduleTask(r.Cron, l.WithValues("action", "create"), bs.Spec.Schedule, bs.Status.ScheduleTaskID, createBackupFunc)
+		id, err := factory.ScheduleBackupTask(r.Cron, l.WithValues("action", "create"), bs.Spec.Schedule, bs.Spec.TimeZone, bs.Spec.Jitter, bs.UID, bs.Status.ScheduleTaskID, createBackupFunc)
 		if err != nil {
 			l.Error(err, "failed to schedule clickhouse backup")
 
 			return ctrl.Result{}, err
 		}
 
+		if err := factory.ObserveScheduleInterval(factory.EngineClickHouse, bs.Name, bs.Namespace, bs.Spec.Schedule); err != nil {
+			l.Error(err, "failed to update schedule interval metric")
+		}
+
+		if r.PrometheusRules {
+			if err := factory.ReconcilePrometheusRule(ctx, r.Client, factory.EngineClickHouse, bs, bs.AsOwner(), bs.Spec.Schedule); err != nil {
+				l.Error(err, "failed to reconcile prometheus rule")
+			}
+		}
+
 		bs.Status.ScheduleTaskID = int(id)
 		bs.Status.ActiveGeneration = bs.Generation
 		bs.Status.UpdatedAt = metav1.Now()
 
-		if bs.Spec.Retention != "" {
-			rd, err := time.ParseDuration(bs.Spec.Retention)
+		if bs.Spec.Audit != nil {
+			auditBackupsFunc := func() {
+				l.V(3).Info("executing backups audit schedule")
+
+				if err := factory.AuditClickHouseBackups(ctx, r.Client, l, req.NamespacedName); err != nil {
+					metrics.ScheduledTaskFailuresByControllerTotal.With(
+						prometheus.Labels{
+							"name":       bs.Name,
+							"namespace":  bs.Namespace,
+							"controller": "clickhousebackupschedule",
+							"action":     "audit",
+						},
+					).Inc()
+
+					l.Error(err, "failed to audit clickhouse backups")
+				}
+			}
+
+			l.V(2).Info("schedule backups audit task")
+
+			id, err := factory.ScheduleTask(r.Cron, l.WithValues("action", "audit"), bs.Spec.Audit.GetSchedule(), bs.Status.AuditTaskID, auditBackupsFunc)
 			if err != nil {
-				l.Error(err, "failed to parse retention duration")
+				l.Error(err, "failed to schedule clickhouse backups audit")
 
 				return ctrl.Result{}, err
 			}
 
-			removeOutdatedBackupsFunc := func() {
-				l.V(3).Info("executing backups remove schedule")
+			bs.Status.AuditTaskID = int(id)
+		} else {
+			factory.RemoveTask(r.Cron, bs.Status.AuditTaskID)
+			bs.Status.AuditTaskID = 0
+		}
 
-				bl := &backupsv1alpha1.ClickHouseBackupList{}
+		if bs.Spec.MaxBackupAge != "" {
+			checkFreshnessFunc := func() {
+				l.V(3).Info("executing backups freshness check schedule")
 
-				if err := r.List(ctx, bl); err != nil {
+				if err := factory.CheckClickHouseBackupsFreshness(ctx, r.Client, l, r.Notifier, req.NamespacedName); err != nil {
 					metrics.ScheduledTaskFailuresByControllerTotal.With(
 						prometheus.Labels{
 							"name":       bs.Name,
 							"namespace":  bs.Namespace,
 							"controller": "clickhousebackupschedule",
-							"action":     "remove",
+							"action":     "freshness",
 						},
 					).Inc()
 
-					l.Error(err, "failed to list clickhouse backup objects")
-
-					return
-				}
-
-				for _, item := range bl.Items {
-					owner := metav1.GetControllerOf(&item)
-					if owner != nil {
-						if bs.UID != owner.UID {
-							continue
-						}
-
-						dt := item.CreationTimestamp.Time
-						if time.Since(dt) > rd {
-							l.V(3).Info("delete backup object", "name", item.Name)
-
-							if err := r.Delete(ctx, &item); err != nil {
-								metrics.ScheduledTaskFailuresByControllerTotal.With(
-									prometheus.Labels{
-										"name":       bs.Name,
-										"namespace":  bs.Namespace,
-										"controller": "clickhousebackupschedule",
-										"action":     "remove",
-									},
-								).Inc()
-
-								l.Error(err, "failed to delete clickhouse backup object")
-							}
-						}
-					}
+					l.Error(err, "failed to check clickhouse backups freshness")
 				}
 			}
 
-			l.V(2).Info("schedule backup rotation task")
+			l.V(2).Info("schedule backups freshness check task")
 
-			id, err := factory.ScheduleTask(r.Cron, l.WithValues("action", "remove"), "@hourly", bs.Status.RetentionTaskID, removeOutdatedBackupsFunc)
+			id, err := factory.ScheduleTask(r.Cron, l.WithValues("action", "freshness"), factory.FreshnessCheckSchedule, bs.Status.FreshnessTaskID, checkFreshnessFunc)
 			if err != nil {
-				l.Error(err, "failed to schedule clickhouse backup")
+				l.Error(err, "failed to schedule clickhouse backups freshness check")
+
+				return ctrl.Result{}, err
+			}
+
+			bs.Status.FreshnessTaskID = int(id)
+		} else {
+			factory.RemoveTask(r.Cron, bs.Status.FreshnessTaskID)
+			bs.Status.FreshnessTaskID = 0
+			meta.RemoveStatusCondition(&bs.Status.Conditions, backupsv1alpha1.ConditionFresh)
+		}
+
+		if err := r.Status().Update(ctx, bs); err != nil {
+			l.Error(err, "failed update clickhouse backup schedule object")
+
+			return ctrl.Result{}, err
+		}
+	}
+
+	if trigger, ok := factory.GetPendingTrigger(bs, bs.Status.LastTrigger); ok {
+		b, err := factory.NewClickHouseScheduleBackup(bs, time.Now())
+		if err != nil {
+			l.Error(err, "failed to get triggered clickhouse backup object")
+
+			return ctrl.Result{}, err
+		}
+
+		b.Name = factory.GetTriggeredBackupName(b.Name, trigger)
+
+		l.V(2).Info("creating triggered backup object", "name", b.Name, "trigger", trigger)
+
+		if err := r.Create(ctx, b); err != nil {
+			if !errors.IsAlreadyExists(err) {
+				l.Error(err, "failed to create triggered clickhouse backup object")
+
+				return ctrl.Result{}, err
+			}
+
+			// trigger is handled only if existing object is created by previous attempt of this schedule
+			existing := &backupsv1alpha1.ClickHouseBackup{}
+			if err := r.Get(ctx, client.ObjectKeyFromObject(b), existing); err != nil {
+				l.Error(err, "failed to get triggered clickhouse backup object")
+
+				return ctrl.Result{}, err
+			}
+
+			if !metav1.IsControlledBy(existing, bs) {
+				err := fmt.Errorf("backup object %s already exists and is not owned by schedule", b.Name)
+				l.Error(err, "failed to create triggered clickhouse backup object")
 
 				return ctrl.Result{}, err
 			}
+		}
+
+		bs.Status.LastTrigger = trigger
+		bs.Status.LastTriggeredBackup = b.Name
+		if err := r.Status().Update(ctx, bs); err != nil {
+			l.Error(err, "failed update clickhouse backup schedule object")
+
+			return ctrl.Result{}, err
+		}
+	}
+
+	var requeueAfter time.Duration
+	if bs.Spec.Retention != "" && !bs.Spec.SuspendRetention {
+		l.V(3).Info("removing outdated backup objects")
+
+		requeueAfter, err = factory.RemoveOutdatedClickHouseBackups(ctx, r.Client, l, r.Notifier, bs)
+		if err != nil {
+			metrics.ScheduledTaskFailuresByControllerTotal.With(
+				prometheus.Labels{
+					"name":       bs.Name,
+					"namespace":  bs.Namespace,
+					"controller": "clickhousebackupschedule",
+					"action":     "remove",
+				},
+			).Inc()
 
-			bs.Status.RetentionTaskID = int(id)
+			l.Error(err, "failed to remove outdated clickhouse backup objects")
+
+			return ctrl.Result{}, err
 		}
+	}
+
+	// owned backups changes trigger reconcile, so history is kept current
+	changed, err := factory.UpdateClickHouseScheduleHistory(ctx, r.Client, bs)
+	if err != nil {
+		l.Error(err, "failed to get clickhouse backup schedule history")
+
+		return ctrl.Result{}, err
+	}
 
+	if changed {
 		if err := r.Status().Update(ctx, bs); err != nil {
 			l.Error(err, "failed update clickhouse backup schedule object")
 
@@ -216,12 +387,18 @@ func (r *ClickHouseBackupScheduleReconciler) Reconcile(ctx context.Context, req
 
 	l.V(1).Info("finished resource reconclie")
 
-	return ctrl.Result{}, nil
+	// retention is executed again, when next backup object expires
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClickHouseBackupScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := factory.IndexOwnerUID(context.Background(), mgr.GetFieldIndexer(), &backupsv1alpha1.ClickHouseBackup{}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&backupsv1alpha1.ClickHouseBackupSchedule{}).
+		Owns(&backupsv1alpha1.ClickHouseBackup{}, builder.OnlyMetadata).
 		Complete(r)
 }
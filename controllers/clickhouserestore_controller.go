@@ -0,0 +1,96 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/controllers/factory"
+	"github.com/sputnik-systems/backups-operator/internal/tracing"
+)
+
+// ClickHouseRestoreReconciler reconciles a ClickHouseRestore object
+type ClickHouseRestoreReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=clickhouserestores,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=clickhouserestores/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=clickhouserestores/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=pods;endpoints;namespaces,verbs=get;list;watch
+
+// restoreRetryInterval is interval of restore retries, while restored backup is not completed
+const restoreRetryInterval = 30 * time.Second
+
+// Reconcile downloads backup by target clickhouse-backup api and restores it
+func (r *ClickHouseRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "ClickHouseRestore.Reconcile", tracing.Object(req.Name, req.Namespace)...)
+	defer func() { tracing.End(span, err) }()
+
+	l := log.FromContext(ctx)
+
+	l.V(1).Info("started resource reconclie")
+
+	cr := &backupsv1alpha1.ClickHouseRestore{}
+	err = r.Get(ctx, req.NamespacedName, cr)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		l.Error(err, "failed to get clickhouse restore object for reconclie")
+
+		return ctrl.Result{}, err
+	}
+
+	if !cr.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	if err := factory.ProccessClickHouseRestoreObject(ctx, r.Client, l, cr); err != nil {
+		if errors.Is(err, factory.ErrBackupNotCompleted) {
+			l.V(1).Info("waiting for backup completion", "backup", cr.Spec.Backup.Name)
+
+			return ctrl.Result{RequeueAfter: restoreRetryInterval}, nil
+		}
+
+		l.Error(err, "failed to process clickhouse restore object")
+
+		return ctrl.Result{}, err
+	}
+
+	l.V(1).Info("finished resource reconclie")
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClickHouseRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&backupsv1alpha1.ClickHouseRestore{}).
+		Complete(r)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/controllers/factory"
+	"github.com/sputnik-systems/backups-operator/internal/tracing"
+)
+
+// ClusterBackupScheduleReconciler reconciles a ClusterBackupSchedule object
+type ClusterBackupScheduleReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=clusterbackupschedules,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=clusterbackupschedules/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=clusterbackupschedules/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// Reconcile keeps backup schedules in namespaces selected by cluster backup schedule
+// in sync with its templates. Schedules are deleted by garbage collector with cluster backup schedule.
+func (r *ClusterBackupScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "ClusterBackupSchedule.Reconcile", tracing.Object(req.Name, req.Namespace)...)
+	defer func() { tracing.End(span, err) }()
+
+	l := log.FromContext(ctx)
+
+	l.V(1).Info("started resource reconclie")
+
+	cs := &backupsv1alpha1.ClusterBackupSchedule{}
+	err = r.Get(ctx, req.NamespacedName, cs)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		l.Error(err, "failed to get cluster backup schedule object for reconclie")
+
+		return ctrl.Result{}, err
+	}
+
+	if !cs.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	if err := factory.ProccessClusterBackupScheduleObject(ctx, r.Client, l, cs); err != nil {
+		l.Error(err, "failed to process cluster backup schedule object")
+
+		return ctrl.Result{}, err
+	}
+
+	l.V(1).Info("finished resource reconclie")
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterBackupScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&backupsv1alpha1.ClusterBackupSchedule{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&backupsv1alpha1.ClickHouseBackupSchedule{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&backupsv1alpha1.DgraphBackupSchedule{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&source.Kind{Type: &corev1.Namespace{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapNamespaceToClusterSchedules),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}),
+		).
+		Complete(r)
+}
+
+// mapNamespaceToClusterSchedules enqueues all cluster backup schedules on namespace changes,
+// because any of them may start or stop selecting it
+func (r *ClusterBackupScheduleReconciler) mapNamespaceToClusterSchedules(obj client.Object) []reconcile.Request {
+	csl := &backupsv1alpha1.ClusterBackupScheduleList{}
+	if err := r.List(context.Background(), csl); err != nil {
+		log.Log.Error(err, "failed to list cluster backup schedule objects")
+
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(csl.Items))
+	for _, cs := range csl.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: cs.Name}})
+	}
+
+	return requests
+}
@@ -29,17 +29,23 @@ import (
 	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
 	"github.com/sputnik-systems/backups-operator/controllers/factory"
 	"github.com/sputnik-systems/backups-operator/internal/metrics"
+	"github.com/sputnik-systems/backups-operator/internal/notify"
+	"github.com/sputnik-systems/backups-operator/internal/tracing"
 )
 
 // DgraphBackupReconciler reconciles a DgraphBackup object
 type DgraphBackupReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Notifier is used to send backup completion and failure notifications
+	Notifier notify.Notifier
 }
 
 //+kubebuilder:rbac:groups=backups.sputnik.systems,resources=dgraphbackups,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=backups.sputnik.systems,resources=dgraphbackups/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=backups.sputnik.systems,resources=dgraphbackups/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -50,13 +56,16 @@ type DgraphBackupReconciler struct {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.9.2/pkg/reconcile
-func (r *DgraphBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *DgraphBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "DgraphBackup.Reconcile", tracing.Object(req.Name, req.Namespace)...)
+	defer func() { tracing.End(span, err) }()
+
 	l := log.FromContext(ctx)
 
 	l.V(1).Info("started resource reconclie")
 
 	b := &backupsv1alpha1.DgraphBackup{}
-	err := r.Get(ctx, req.NamespacedName, b)
+	err = r.Get(ctx, req.NamespacedName, b)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return ctrl.Result{}, nil
@@ -73,18 +82,20 @@ func (r *DgraphBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			l.Error(err, "failed to delete dgraph backup object")
 		}
 
-		metrics.BackupsByController.Delete(
-			prometheus.Labels{
-				"name":       b.Name,
-				"namespace":  b.Namespace,
-				"controller": "dgraphbackup",
-			},
-		)
+		factory.DeleteBackupMetrics(factory.EngineDgraph, "dgraphbackup", b, b.Status.Phase)
 
 		return ctrl.Result{}, err
 	}
 
-	if err := factory.ProccessDgraphBackupObject(ctx, r.Client, b); err != nil {
+	phase := b.Status.Phase
+
+	err = factory.ProccessDgraphBackupObject(ctx, r.Client, l, b)
+	if b.Status.Phase != phase {
+		factory.ObservePhaseTransition(factory.EngineDgraph, b, phase, b.Status.Phase)
+		factory.NotifyPhaseTransition(ctx, l, r.Notifier, factory.EngineDgraph, b, b.Status.Phase, b.Status.Error, b.Status.Size)
+	}
+
+	if err != nil {
 		metrics.BackupsByController.With(
 			prometheus.Labels{
 				"name":       b.Name,
@@ -0,0 +1,98 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/controllers/factory"
+	"github.com/sputnik-systems/backups-operator/internal/tracing"
+)
+
+// DgraphBackupImportReconciler reconciles a DgraphBackupImport object
+type DgraphBackupImportReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=dgraphbackupimports,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=dgraphbackupimports/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=dgraphbackupimports/finalizers,verbs=update
+
+// Reconcile scans remote storage and creates backup objects for backups,
+// which are not known by operator yet.
+func (r *DgraphBackupImportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "DgraphBackupImport.Reconcile", tracing.Object(req.Name, req.Namespace)...)
+	defer func() { tracing.End(span, err) }()
+
+	l := log.FromContext(ctx)
+
+	l.V(1).Info("started resource reconclie")
+
+	bi := &backupsv1alpha1.DgraphBackupImport{}
+	err = r.Get(ctx, req.NamespacedName, bi)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		l.Error(err, "failed to get dgraph backup import object for reconclie")
+
+		return ctrl.Result{}, err
+	}
+
+	if !bi.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	var interval time.Duration
+	if bi.Spec.Interval != "" {
+		interval, err = time.ParseDuration(bi.Spec.Interval)
+		if err != nil {
+			l.Error(err, "failed to parse scan interval")
+
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := factory.ProccessDgraphBackupImportObject(ctx, r.Client, l, bi); err != nil {
+		l.Error(err, "failed to process dgraph backup import object")
+
+		return ctrl.Result{}, err
+	}
+
+	l.V(1).Info("finished resource reconclie")
+
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DgraphBackupImportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&backupsv1alpha1.DgraphBackupImport{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,302 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/clickhouse"
+	"github.com/sputnik-systems/backups-operator/internal/dgraph"
+	"github.com/sputnik-systems/backups-operator/internal/metrics"
+)
+
+// AuditClickHouseBackups compares schedule backup objects with remote storage content
+func AuditClickHouseBackups(ctx context.Context, rc client.Client, l logr.Logger, key types.NamespacedName) error {
+	bs := &backupsv1alpha1.ClickHouseBackupSchedule{}
+	if err := rc.Get(ctx, key, bs); err != nil {
+		return fmt.Errorf("failed to get clickhouse backup schedule object: %w", err)
+	}
+
+	audit, err := auditClickHouseBackups(ctx, rc, l, bs)
+	if err != nil {
+		audit.Error = err.Error()
+	}
+
+	updateAuditMetrics(bs.Name, bs.Namespace, "clickhousebackupschedule", audit)
+
+	bs.Status.Audit = audit
+	if err := rc.Status().Update(ctx, bs); err != nil {
+		return fmt.Errorf("failed update clickhouse backup schedule object: %w", err)
+	}
+
+	return err
+}
+
+// AuditDgraphBackups compares schedule backup objects with remote storage content
+func AuditDgraphBackups(ctx context.Context, rc client.Client, l logr.Logger, key types.NamespacedName) error {
+	bs := &backupsv1alpha1.DgraphBackupSchedule{}
+	if err := rc.Get(ctx, key, bs); err != nil {
+		return fmt.Errorf("failed to get dgraph backup schedule object: %w", err)
+	}
+
+	audit, err := auditDgraphBackups(ctx, rc, l, bs)
+	if err != nil {
+		audit.Error = err.Error()
+	}
+
+	updateAuditMetrics(bs.Name, bs.Namespace, "dgraphbackupschedule", audit)
+
+	bs.Status.Audit = audit
+	if err := rc.Status().Update(ctx, bs); err != nil {
+		return fmt.Errorf("failed update dgraph backup schedule object: %w", err)
+	}
+
+	return err
+}
+
+// DeleteAuditMetrics removes audit metrics of given schedule
+func DeleteAuditMetrics(name, namespace, controller string) {
+	labels := prometheus.Labels{
+		"name":       name,
+		"namespace":  namespace,
+		"controller": controller,
+	}
+
+	metrics.MissingBackupsByController.Delete(labels)
+	metrics.OrphanedBackupsByController.Delete(labels)
+}
+
+func auditClickHouseBackups(ctx context.Context, rc client.Client, l logr.Logger, bs *backupsv1alpha1.ClickHouseBackupSchedule) (*backupsv1alpha1.BackupAuditStatus, error) {
+	audit := &backupsv1alpha1.BackupAuditStatus{LastAuditTime: metav1.Now()}
+
+	address, err := getFQDN(bs.Spec.Backup.ApiAddress, bs.Namespace)
+	if err != nil {
+		return audit, fmt.Errorf("failed to get resource fqdn: %w", err)
+	}
+
+	c, err := newClickHouseClient(ctx, rc, bs.Namespace, &bs.Spec.Backup, address, "")
+	if err != nil {
+		return audit, fmt.Errorf("failed to create clickhouse-backup api client: %w", err)
+	}
+
+	backups, err := c.ListBackups(ctx)
+	if err != nil {
+		return audit, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	remote := make(map[string]clickhouse.Backup)
+	for _, backup := range backups {
+		if backup.Location == clickhouse.LocationRemote {
+			remote[backup.Name] = backup
+		}
+	}
+
+	bl := &backupsv1alpha1.ClickHouseBackupList{}
+	if err := rc.List(ctx, bl, client.InNamespace(bs.Namespace)); err != nil {
+		return audit, fmt.Errorf("failed to list clickhouse backup objects: %w", err)
+	}
+
+	known := make(map[string]struct{})
+	for i := range bl.Items {
+		b := &bl.Items[i]
+		known[b.BackupName()] = struct{}{}
+
+		if !metav1.IsControlledBy(b, bs) || !isAuditablePhase(b.Status.Phase) {
+			continue
+		}
+
+		_, found := remote[b.BackupName()]
+		if !found {
+			l.V(3).Info("backup data not found in remote storage", "name", b.Name)
+
+			audit.Missing++
+		}
+
+		if err := updateMissingCondition(ctx, rc, b, &b.Status.Phase, &b.Status.Conditions, !found); err != nil {
+			return audit, fmt.Errorf("failed update clickhouse backup object: %w", err)
+		}
+	}
+
+	orphansRetention, err := bs.Spec.Audit.GetDeleteOrphansAfter()
+	if err != nil {
+		return audit, err
+	}
+
+	for name, backup := range remote {
+		if _, ok := known[name]; ok {
+			continue
+		}
+
+		// only remote backups created by this schedule and not retained by deletion policy are deleted
+		if orphansRetention > 0 && isContains(bs.Status.RemoteBackups, name) {
+			if created, err := backup.CreatedTime(); err == nil && time.Since(created) > orphansRetention {
+				l.V(3).Info("delete orphaned remote backup", "backup", name)
+
+				if err := c.DeleteBackup(ctx, clickhouse.LocationRemote, name); err != nil {
+					return audit, fmt.Errorf("failed to delete orphaned backup: %w", err)
+				}
+
+				delete(remote, name)
+
+				continue
+			}
+		}
+
+		audit.Orphans = append(audit.Orphans, name)
+	}
+
+	bs.Status.RemoteBackups = getRecordedRemoteBackups(bs.Status.RemoteBackups, known, func(name string) bool {
+		_, ok := remote[name]
+		return ok
+	})
+
+	return audit, nil
+}
+
+func auditDgraphBackups(ctx context.Context, rc client.Client, l logr.Logger, bs *backupsv1alpha1.DgraphBackupSchedule) (*backupsv1alpha1.BackupAuditStatus, error) {
+	audit := &backupsv1alpha1.BackupAuditStatus{LastAuditTime: metav1.Now()}
+
+	creds, err := getStorageCredentials(ctx, rc, &bs.Spec.Backup, bs.Namespace)
+	if err != nil {
+		return audit, fmt.Errorf("failed to get creds: %w", err)
+	}
+
+	exports, err := dgraph.ListExports(ctx, &bs.Spec.Backup, creds)
+	if err != nil {
+		return audit, fmt.Errorf("failed to list exports: %w", err)
+	}
+
+	remote := make(map[string]dgraph.ExportInfo)
+	for _, export := range exports {
+		remote[export.Name] = export
+	}
+
+	bl := &backupsv1alpha1.DgraphBackupList{}
+	if err := rc.List(ctx, bl, client.InNamespace(bs.Namespace)); err != nil {
+		return audit, fmt.Errorf("failed to list dgraph backup objects: %w", err)
+	}
+
+	known := make(map[string]struct{})
+	for i := range bl.Items {
+		b := &bl.Items[i]
+
+		dir := dgraph.GetExportDir(b)
+		if dir == "" {
+			continue
+		}
+
+		known[dir] = struct{}{}
+
+		if !metav1.IsControlledBy(b, bs) || !isAuditablePhase(b.Status.Phase) {
+			continue
+		}
+
+		_, found := remote[dir]
+		if !found {
+			l.V(3).Info("backup data not found in remote storage", "name", b.Name)
+
+			audit.Missing++
+		}
+
+		if err := updateMissingCondition(ctx, rc, b, &b.Status.Phase, &b.Status.Conditions, !found); err != nil {
+			return audit, fmt.Errorf("failed update dgraph backup object: %w", err)
+		}
+	}
+
+	orphansRetention, err := bs.Spec.Audit.GetDeleteOrphansAfter()
+	if err != nil {
+		return audit, err
+	}
+
+	for name, export := range remote {
+		if _, ok := known[name]; ok {
+			continue
+		}
+
+		// only exports created by this schedule backups are deleted
+		if orphansRetention > 0 && isContains(bs.Status.RemoteBackups, name) {
+			if time.Since(export.ModTime) > orphansRetention {
+				l.V(3).Info("delete orphaned remote backup", "backup", name)
+
+				if err := dgraph.DeleteExportDir(ctx, &bs.Spec.Backup, creds, name); err != nil {
+					return audit, fmt.Errorf("failed to delete orphaned backup: %w", err)
+				}
+
+				delete(remote, name)
+
+				continue
+			}
+		}
+
+		audit.Orphans = append(audit.Orphans, name)
+	}
+
+	bs.Status.RemoteBackups = getRecordedRemoteBackups(bs.Status.RemoteBackups, known, func(name string) bool {
+		_, ok := remote[name]
+		return ok
+	})
+
+	return audit, nil
+}
+
+// getRecordedRemoteBackups returns recorded remote backups, which have backup objects or still exist in remote storage
+func getRecordedRemoteBackups(recorded []string, known map[string]struct{}, exists func(name string) bool) []string {
+	var out []string
+	for _, name := range recorded {
+		if _, ok := known[name]; ok || exists(name) {
+			out = append(out, name)
+		}
+	}
+
+	return out
+}
+
+// updateMissingCondition sets backup object phase and condition according to remote data availability
+func updateMissingCondition(ctx context.Context, rc client.Client, obj client.Object, phase *string, conditions *[]metav1.Condition, missing bool) error {
+	condition := metav1.Condition{
+		Type:    backupsv1alpha1.ConditionMissing,
+		Status:  metav1.ConditionFalse,
+		Reason:  "RemoteBackupFound",
+		Message: "backup data found in remote storage",
+	}
+	expected := PhaseCompleted
+
+	if missing {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "RemoteBackupNotFound"
+		condition.Message = "backup data not found in remote storage"
+		expected = PhaseMissing
+	}
+
+	if *phase == expected && meta.IsStatusConditionPresentAndEqual(*conditions, condition.Type, condition.Status) {
+		return nil
+	}
+
+	*phase = expected
+	meta.SetStatusCondition(conditions, condition)
+
+	return rc.Status().Update(ctx, obj)
+}
+
+func updateAuditMetrics(name, namespace, controller string, audit *backupsv1alpha1.BackupAuditStatus) {
+	labels := prometheus.Labels{
+		"name":       name,
+		"namespace":  namespace,
+		"controller": controller,
+	}
+
+	metrics.MissingBackupsByController.With(labels).Set(float64(audit.Missing))
+	metrics.OrphanedBackupsByController.With(labels).Set(float64(len(audit.Orphans)))
+}
+
+func isAuditablePhase(phase string) bool {
+	return phase == PhaseCompleted || phase == PhaseMissing
+}
@@ -0,0 +1,184 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+)
+
+// defaultDeletionGracePeriod is used, when backup policy deletion grace period is not set
+const defaultDeletionGracePeriod = time.Hour
+
+// policyTemplateData is values available in backup policy templates
+type policyTemplateData struct {
+	Name      string
+	Namespace string
+	Target    string
+	Host      string
+	Labels    map[string]string
+}
+
+// ProccessBackupPolicyObject creates and updates backup schedules for discovered objects
+// and deletes schedules of objects, which are not discovered anymore during deletion grace period.
+// Time until next schedule deletion is returned.
+func ProccessBackupPolicyObject(ctx context.Context, rc client.Client, l logr.Logger, bp *backupsv1alpha1.BackupPolicy) (time.Duration, error) {
+	grace := defaultDeletionGracePeriod
+	if bp.Spec.DeletionGracePeriod != "" {
+		d, err := time.ParseDuration(bp.Spec.DeletionGracePeriod)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse deletion grace period: %w", err)
+		}
+
+		grace = d
+	}
+
+	targets, err := getBackupPolicyTargets(ctx, rc, bp)
+	if err != nil {
+		return 0, err
+	}
+
+	discovered := make(map[string]struct{})
+	var errs []error
+	for _, target := range targets {
+		name := fmt.Sprintf("%s-%s", bp.Name, target.Target)
+		discovered[fmt.Sprintf("%s/%s", target.Namespace, name)] = struct{}{}
+
+		target.Name = bp.Name
+
+		om := metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       target.Namespace,
+			Labels:          map[string]string{backupsv1alpha1.BackupPolicyLabel: bp.Name},
+			OwnerReferences: bp.AsOwner(),
+		}
+
+		if bp.Spec.ClickHouse != nil {
+			if err := syncClickHouseBackupSchedule(ctx, rc, l, bp, om, bp.Spec.ClickHouse, target); err != nil {
+				errs = append(errs, fmt.Errorf("%s/%s: %w", target.Namespace, target.Target, err))
+			}
+		}
+
+		if bp.Spec.Dgraph != nil {
+			if err := syncDgraphBackupSchedule(ctx, rc, l, bp, om, bp.Spec.Dgraph, target); err != nil {
+				errs = append(errs, fmt.Errorf("%s/%s: %w", target.Namespace, target.Target, err))
+			}
+		}
+	}
+
+	// schedules are kept for discovered objects, if their engine template is set
+	keep := func(engine string, bs metav1.Object) bool {
+		if _, ok := discovered[fmt.Sprintf("%s/%s", bs.GetNamespace(), bs.GetName())]; !ok {
+			return false
+		}
+
+		return (engine == EngineClickHouse && bp.Spec.ClickHouse != nil) || (engine == EngineDgraph && bp.Spec.Dgraph != nil)
+	}
+
+	next, err := deleteBackupSchedules(ctx, rc, l, bp, client.MatchingLabels{backupsv1alpha1.BackupPolicyLabel: bp.Name}, keep, grace)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	bp.Status.Targets = make([]string, 0, len(targets))
+	for _, target := range targets {
+		bp.Status.Targets = append(bp.Status.Targets, fmt.Sprintf("%s/%s", target.Namespace, target.Target))
+	}
+	sort.Strings(bp.Status.Targets)
+
+	bp.Status.Discovered = len(bp.Status.Targets)
+	bp.Status.LastSyncTime = metav1.Now()
+	bp.Status.Error = ""
+	if len(errs) > 0 {
+		bp.Status.Error = fmt.Sprintf("%v", errs)
+	}
+
+	if err := rc.Status().Update(ctx, bp); err != nil {
+		return 0, fmt.Errorf("failed update backup policy object: %w", err)
+	}
+
+	if len(errs) > 0 {
+		return 0, fmt.Errorf("failed to sync backup schedules: %v", errs)
+	}
+
+	return next, nil
+}
+
+// getBackupPolicyTargets returns template values of objects matching policy selectors
+func getBackupPolicyTargets(ctx context.Context, rc client.Client, bp *backupsv1alpha1.BackupPolicy) ([]policyTemplateData, error) {
+	namespaces, err := getSelectedNamespaces(ctx, rc, bp.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]struct{})
+	for _, ns := range namespaces {
+		selected[ns.Name] = struct{}{}
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&bp.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse selector: %w", err)
+	}
+
+	targets := make([]policyTemplateData, 0)
+	switch bp.GetTargetKind() {
+	case backupsv1alpha1.BackupPolicyTargetService:
+		sl := &corev1.ServiceList{}
+		if err := rc.List(ctx, sl, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, fmt.Errorf("failed to list services: %w", err)
+		}
+
+		for _, svc := range sl.Items {
+			if _, ok := selected[svc.Namespace]; !ok {
+				continue
+			}
+
+			targets = append(targets, policyTemplateData{
+				Namespace: svc.Namespace,
+				Target:    svc.Name,
+				Host:      fmt.Sprintf("%s.%s.svc", svc.Name, svc.Namespace),
+				Labels:    svc.Labels,
+			})
+		}
+	case backupsv1alpha1.BackupPolicyTargetPod:
+		pl := &corev1.PodList{}
+		if err := rc.List(ctx, pl, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+
+		for _, pod := range pl.Items {
+			if _, ok := selected[pod.Namespace]; !ok {
+				continue
+			}
+
+			// pod ip is changed on restart, so only pods with stable dns name are discovered
+			if pod.Spec.Subdomain == "" {
+				continue
+			}
+
+			hostname := pod.Spec.Hostname
+			if hostname == "" {
+				hostname = pod.Name
+			}
+
+			targets = append(targets, policyTemplateData{
+				Namespace: pod.Namespace,
+				Target:    pod.Name,
+				Host:      fmt.Sprintf("%s.%s.%s.svc", hostname, pod.Spec.Subdomain, pod.Namespace),
+				Labels:    pod.Labels,
+			})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported target kind %q", bp.Spec.TargetKind)
+	}
+
+	return targets, nil
+}
@@ -2,27 +2,46 @@ package factory
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
-	"strconv"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
 	"github.com/sputnik-systems/backups-operator/controllers/factory/finalize"
 	"github.com/sputnik-systems/backups-operator/internal/clickhouse"
+	"github.com/sputnik-systems/backups-operator/internal/tracing"
 )
 
 func ProccessClickHouseBackupObject(ctx context.Context, rc client.Client, l logr.Logger, b *backupsv1alpha1.ClickHouseBackup) error {
+	// imported backup status is set by import controller
+	if backupsv1alpha1.IsImported(b) {
+		return nil
+	}
+
+	// trace id of last processing reconcile helps to find it in tracing backend
+	if id := tracing.TraceID(ctx); id != "" {
+		b.Status.TraceID = id
+	}
+
 	if b.Status.Phase == "" {
 		if err := finalize.AddFinalizer(ctx, rc, b); err != nil {
 			return fmt.Errorf("failed to add finalizer: %w", err)
 		}
 
-		b.Status.Phase = PhaseStarted
+		setClickHouseBackupPhase(b, PhaseStarted)
 		if err := rc.Status().Update(ctx, b); err != nil {
 			return fmt.Errorf("failed update status: %w", err)
 		}
@@ -32,63 +51,408 @@ func ProccessClickHouseBackupObject(ctx context.Context, rc client.Client, l log
 		}
 	}
 
-	if err := createClickHouseBackup(ctx, rc, l, b); err != nil {
+	if err := checkClickHouseBackupTargetPod(ctx, rc, l, b); err != nil {
+		return fmt.Errorf("failed to check target pod: %w", err)
+	}
+
+	if b.Status.Phase == PhaseStarted {
+		if err := processClickHouseBackupHooks(ctx, rc, l, b, backupsv1alpha1.BackupHookStagePre); err != nil {
+			return fmt.Errorf("failed to run pre hooks: %w", err)
+		}
+	}
+
+	c, err := newClickHouseClient(ctx, rc, b.Namespace, &b.Spec, b.Status.Api.Address, b.Status.Api.Hostname)
+	if err != nil {
+		return fmt.Errorf("failed to create clickhouse-backup api client: %w", err)
+	}
+
+	if err := createClickHouseBackup(ctx, rc, l, c, b); err != nil {
 		return fmt.Errorf("failed to create backup: %w", err)
 	}
 
-	if err := uploadClickHouseBackup(ctx, rc, l, b); err != nil {
+	if err := uploadClickHouseBackup(ctx, rc, l, c, b); err != nil {
 		return fmt.Errorf("failed to upload backup: %w", err)
 	}
 
+	if isContains(hookFinishedPhases, b.Status.Phase) {
+		if err := processClickHouseBackupHooks(ctx, rc, l, b, backupsv1alpha1.BackupHookStagePost); err != nil {
+			return fmt.Errorf("failed to run post hooks: %w", err)
+		}
+	}
+
 	return nil
 }
 
-func DeleteClickHouseBackupObject(ctx context.Context, rc client.Client, b *backupsv1alpha1.ClickHouseBackup) error {
-	var err error
+// processClickHouseBackupHooks runs backup hooks, exec hooks are run in backup target pod by default
+func processClickHouseBackupHooks(ctx context.Context, rc client.Client, l logr.Logger, b *backupsv1alpha1.ClickHouseBackup, stage backupsv1alpha1.BackupHookStage) error {
+	phase := b.Status.Phase
+	err := processBackupHooks(ctx, rc, l, b, b.Spec.Hooks, stage, &b.Status.Phase, &b.Status.Error, &b.Status.Hooks, b.Status.Api.PodName)
+	if b.Status.Phase != phase {
+		ObservePhaseTransition(EngineClickHouse, b, phase, b.Status.Phase)
+	}
+
+	return err
+}
+
+// setClickHouseBackupPhase sets backup phase and observes its transition.
+// Backup is created and uploaded within one reconcile, so intermediate phases are observed here.
+func setClickHouseBackupPhase(b *backupsv1alpha1.ClickHouseBackup, phase string) {
+	if b.Status.Phase == phase {
+		return
+	}
+
+	ObservePhaseTransition(EngineClickHouse, b, b.Status.Phase, phase)
+	b.Status.Phase = phase
+}
+
+func DeleteClickHouseBackupObject(ctx context.Context, rc client.Client, b *backupsv1alpha1.ClickHouseBackup) (err error) {
+	ctx, span := tracing.Start(ctx, "ClickHouseBackup.Finalize", tracing.Object(b.Name, b.Namespace)...)
+	defer func() { tracing.End(span, err) }()
 
-	if b.Status.Phase == PhaseCreateFailed {
-		b.Spec.ApiAddress, err = getFQDN(b.Spec.ApiAddress, b.Namespace)
+	locations := getClickHouseBackupDeletionLocations(b)
+	if len(locations) > 0 {
+		address, hostname, err := getClickHouseBackupDeletionAddress(ctx, rc, b)
 		if err != nil {
-			return fmt.Errorf("failed to get resource fqdn: %w", err)
+			return err
 		}
 
-		if _, err = clickhouse.DeleteBackup(ctx, b); err != nil {
+		c, err := newClickHouseClient(ctx, rc, b.Namespace, &b.Spec, address, hostname)
+		if err != nil {
+			return fmt.Errorf("failed to create clickhouse-backup api client: %w", err)
+		}
+
+		backups, err := c.ListBackups(ctx)
+		if err != nil {
 			return fmt.Errorf("failed to delete backup: %w", err)
 		}
+
+		for _, backup := range backups {
+			if backup.Name != b.BackupName() || !isContains(locations, backup.Location) {
+				continue
+			}
+
+			if err := c.DeleteBackup(ctx, backup.Location, backup.Name); err != nil {
+				return fmt.Errorf("failed to delete backup: %w", err)
+			}
+		}
 	}
 
-	if err = finalize.RemoveFinalizeObjByName(ctx, rc, b, b.Name, b.Namespace); err != nil {
+	// retained remote backup must not be deleted by schedule orphans audit
+	if !isContains(locations, clickhouse.LocationRemote) {
+		if err := forgetClickHouseScheduleRemoteBackup(ctx, rc, b); err != nil {
+			return err
+		}
+	}
+
+	if err := finalize.RemoveFinalizeObjByName(ctx, rc, b, b.Name, b.Namespace); err != nil {
 		return fmt.Errorf("failed to remove finalizer: %w", err)
 	}
 
 	return nil
 }
 
-func updateClickHouseBackupObjectStatusApiInfo(ctx context.Context, rc client.Client, b *backupsv1alpha1.ClickHouseBackup) error {
-	var err error
+// getClickHouseBackupDeletionAddress returns api address and hostname of backup target pod, which keeps local backup.
+// Target pod is resolved again, when it is gone together with local backup.
+func getClickHouseBackupDeletionAddress(ctx context.Context, rc client.Client, b *backupsv1alpha1.ClickHouseBackup) (string, string, error) {
+	address, err := getFQDN(b.Spec.ApiAddress, b.Namespace)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get resource fqdn: %w", err)
+	}
 
-	b.Spec.ApiAddress, err = getFQDN(b.Spec.ApiAddress, b.Namespace)
+	hostname, err := getHostname(address)
 	if err != nil {
-		return fmt.Errorf("failed to get resource fqdn: %w", err)
+		return "", "", fmt.Errorf("failed to get resource hostname: %w", err)
+	}
+
+	var pod *v1.Pod
+	if b.Status.Api.PodName != "" {
+		pod = &v1.Pod{}
+		if err := rc.Get(ctx, types.NamespacedName{Name: b.Status.Api.PodName, Namespace: b.Namespace}, pod); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return "", "", fmt.Errorf("failed to get pod: %w", err)
+			}
+
+			pod = nil
+		}
+	}
+
+	if pod == nil {
+		pod, err = getClickHouseBackupTargetPod(ctx, rc, b.Namespace, b.Spec.PodSelector, address)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get target pod: %w", err)
+		}
+	}
+
+	// api is not served by kubernetes pod, so requests are sent to given address
+	if pod == nil {
+		return address, "", nil
 	}
 
-	b.Status.Api.Address, err = getUrlWithIP(b.Spec.ApiAddress)
+	// pod address is changed, when it is restarted, so it is not taken from status
+	address, err = getUrlWithHost(address, pod.Status.PodIP)
 	if err != nil {
-		return fmt.Errorf("failed to get resource ip address: %w", err)
+		return "", "", fmt.Errorf("failed to get pod address: %w", err)
 	}
 
-	b.Status.Api.Hostname, err = getHostname(b.Spec.ApiAddress)
+	return address, hostname, nil
+}
+
+// forgetClickHouseScheduleRemoteBackup removes backup from remote backups recorded by owner schedule
+func forgetClickHouseScheduleRemoteBackup(ctx context.Context, rc client.Client, b *backupsv1alpha1.ClickHouseBackup) error {
+	owner := metav1.GetControllerOf(b)
+	if owner == nil || owner.Kind != "ClickHouseBackupSchedule" {
+		return nil
+	}
+
+	bs := &backupsv1alpha1.ClickHouseBackupSchedule{}
+	if err := rc.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: b.Namespace}, bs); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to get clickhouse backup schedule object: %w", err)
+	}
+
+	if bs.UID != owner.UID || !isContains(bs.Status.RemoteBackups, b.BackupName()) {
+		return nil
+	}
+
+	bs.Status.RemoteBackups = removeString(bs.Status.RemoteBackups, b.BackupName())
+	if err := rc.Status().Update(ctx, bs); err != nil {
+		return fmt.Errorf("failed update clickhouse backup schedule object: %w", err)
+	}
+
+	return nil
+}
+
+// updateClickHouseBackupSize sets backup status size and size metric from uploaded remote backup
+func updateClickHouseBackupSize(ctx context.Context, c *clickhouse.Client, b *backupsv1alpha1.ClickHouseBackup) error {
+	backups, err := c.ListBackups(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, backup := range backups {
+		if backup.Name == b.BackupName() && backup.Location == clickhouse.LocationRemote {
+			b.Status.Size = backup.Size
+			observeBackupSize(EngineClickHouse, b, backup.Size)
+
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// getClickHouseBackupDeletionLocations returns backup locations, which should be cleaned up according to deletion policy
+func getClickHouseBackupDeletionLocations(b *backupsv1alpha1.ClickHouseBackup) []string {
+	switch b.Spec.DeletionPolicy {
+	case backupsv1alpha1.ClickHouseBackupDeletionPolicyRetain:
+		// partially created backup is useless, so it should not take sidecar disk space
+		if b.Status.Phase == PhaseCreateFailed {
+			return []string{clickhouse.LocationLocal}
+		}
+
+		return nil
+	case backupsv1alpha1.ClickHouseBackupDeletionPolicyDeleteLocalOnly:
+		return []string{clickhouse.LocationLocal}
+	default:
+		return []string{clickhouse.LocationLocal, clickhouse.LocationRemote}
+	}
+}
+
+func updateClickHouseBackupObjectStatusApiInfo(ctx context.Context, rc client.Client, b *backupsv1alpha1.ClickHouseBackup) error {
+	address, err := getFQDN(b.Spec.ApiAddress, b.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get resource fqdn: %w", err)
+	}
+
+	b.Status.Api.Hostname, err = getHostname(address)
 	if err != nil {
 		return fmt.Errorf("failed to get resource hostname: %w", err)
 	}
 
+	pod, err := getClickHouseBackupTargetPod(ctx, rc, b.Namespace, b.Spec.PodSelector, address)
+	if err != nil {
+		return fmt.Errorf("failed to get target pod: %w", err)
+	}
+
+	if err := setClickHouseBackupStatusApiPod(b, address, pod); err != nil {
+		return err
+	}
+
+	return rc.Status().Update(ctx, b)
+}
+
+// setClickHouseBackupStatusApiPod sets status api fields, so requests are sent to given pod.
+// Nil pod means api is not served by kubernetes pod, so requests are sent to given address.
+func setClickHouseBackupStatusApiPod(b *backupsv1alpha1.ClickHouseBackup, address string, pod *v1.Pod) error {
+	b.Status.Api.Address = address
+	b.Status.Api.PodName = ""
+	b.Status.Api.PodUID = ""
+	b.Status.Api.RestartCount = 0
+
+	if pod == nil {
+		return nil
+	}
+
+	var err error
+	b.Status.Api.Address, err = getUrlWithHost(address, pod.Status.PodIP)
+	if err != nil {
+		return fmt.Errorf("failed to get pod address: %w", err)
+	}
+
+	b.Status.Api.PodName = pod.Name
+	b.Status.Api.PodUID = string(pod.UID)
+	b.Status.Api.RestartCount = getPodRestartCount(pod)
+
+	return nil
+}
+
+// getClickHouseBackupTargetPod returns ready pod serving clickhouse-backup api.
+// Pods are chosen by pod selector or api address service endpoints.
+// Nil pod is returned, if api address is not kubernetes service.
+func getClickHouseBackupTargetPod(ctx context.Context, rc client.Client, ns string, podSelector *metav1.LabelSelector, address string) (*v1.Pod, error) {
+	if podSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(podSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse pod selector: %w", err)
+		}
+
+		pl := &v1.PodList{}
+		if err := rc.List(ctx, pl, client.InNamespace(ns), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+
+		sort.Slice(pl.Items, func(i, j int) bool {
+			return pl.Items[i].Name < pl.Items[j].Name
+		})
+
+		for i := range pl.Items {
+			if isPodReady(&pl.Items[i]) {
+				return &pl.Items[i], nil
+			}
+		}
+
+		return nil, errors.New("ready pod not found by selector")
+	}
+
+	hostname, err := getHostname(address)
+	if err != nil {
+		return nil, err
+	}
+
+	// only <service>.<namespace>.svc[.<cluster domain>] addresses are resolved through endpoints
+	labels := strings.Split(hostname, ".")
+	if len(labels) < 3 || labels[2] != "svc" {
+		return nil, nil
+	}
+
+	ep := &v1.Endpoints{}
+	if err := rc.Get(ctx, types.NamespacedName{Name: labels[0], Namespace: labels[1]}, ep); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to get service endpoints: %w", err)
+	}
+
+	names := make([]string, 0)
+	for _, subset := range ep.Subsets {
+		for _, addr := range subset.Addresses {
+			if addr.TargetRef != nil && addr.TargetRef.Kind == "Pod" {
+				names = append(names, addr.TargetRef.Name)
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, errors.New("ready pod not found in service endpoints")
+	}
+
+	sort.Strings(names)
+
+	pod := &v1.Pod{}
+	if err := rc.Get(ctx, types.NamespacedName{Name: names[0], Namespace: labels[1]}, pod); err != nil {
+		return nil, fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	return pod, nil
+}
+
+// checkClickHouseBackupTargetPod detects target pod restarts during backup processing.
+// Backup creation can not be continued on restarted pod, so it is marked as failed.
+// Created backup is kept on pod volume, so uploading is started again on the same pod, when it is ready.
+// If target pod is gone, created backup is lost with it, so backup is created again on another pod.
+func checkClickHouseBackupTargetPod(ctx context.Context, rc client.Client, l logr.Logger, b *backupsv1alpha1.ClickHouseBackup) error {
+	if b.Status.Api.PodName == "" {
+		return nil
+	}
+
+	switch b.Status.Phase {
+	case PhaseCreating, PhaseCreated, PhaseUploading:
+	default:
+		return nil
+	}
+
+	pod := &v1.Pod{}
+	err := rc.Get(ctx, types.NamespacedName{Name: b.Status.Api.PodName, Namespace: b.Namespace}, pod)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	if err == nil && string(pod.UID) == b.Status.Api.PodUID && getPodRestartCount(pod) == b.Status.Api.RestartCount {
+		return nil
+	}
+
+	l.V(4).Info("target pod restarted", "pod", b.Status.Api.PodName, "phase", b.Status.Phase)
+
+	if b.Status.Phase == PhaseCreating {
+		setClickHouseBackupPhase(b, PhaseCreateFailed)
+		b.Status.Error = fmt.Sprintf("pod %s restarted during backup creation", b.Status.Api.PodName)
+
+		return rc.Status().Update(ctx, b)
+	}
+
+	b.Status.OperationID = ""
+
+	if apierrors.IsNotFound(err) {
+		l.V(4).Info("target pod not found, creating backup again", "pod", b.Status.Api.PodName)
+
+		setClickHouseBackupPhase(b, PhaseStarted)
+
+		return updateClickHouseBackupObjectStatusApiInfo(ctx, rc, b)
+	}
+
+	// other replicas do not have created backup, so only the same pod is used for uploading
+	if !isPodReady(pod) {
+		return fmt.Errorf("pod %s is not ready after restart", pod.Name)
+	}
+
+	address, err := getFQDN(b.Spec.ApiAddress, b.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get resource fqdn: %w", err)
+	}
+
+	if err := setClickHouseBackupStatusApiPod(b, address, pod); err != nil {
+		return err
+	}
+
+	setClickHouseBackupPhase(b, PhaseCreated)
+
 	return rc.Status().Update(ctx, b)
 }
 
-func createClickHouseBackup(ctx context.Context, rc client.Client, l logr.Logger, b *backupsv1alpha1.ClickHouseBackup) error {
+func createClickHouseBackup(ctx context.Context, rc client.Client, l logr.Logger, c *clickhouse.Client, b *backupsv1alpha1.ClickHouseBackup) error {
 	if b.Status.Phase == PhaseStarted {
-		if _, err := clickhouse.CreateBackup(ctx, b); err != nil {
-			b.Status.Phase = PhaseCreateFailed
+		ack, err := c.CreateBackup(ctx, b.BackupName(), b.Spec.CreateParams)
+		if err != nil {
+			// another operation is in progress, so creation will be retried on next reconcile
+			if clickhouse.IsLocked(err) {
+				return err
+			}
+
+			setClickHouseBackupPhase(b, PhaseCreateFailed)
 			b.Status.Error = err.Error()
 			if err := rc.Status().Update(ctx, b); err != nil {
 				return err
@@ -97,7 +461,8 @@ func createClickHouseBackup(ctx context.Context, rc client.Client, l logr.Logger
 			return err
 		}
 
-		b.Status.Phase = PhaseCreating
+		setClickHouseBackupPhase(b, PhaseCreating)
+		b.Status.OperationID = ack.OperationID
 		if err := rc.Status().Update(ctx, b); err != nil {
 			return fmt.Errorf("failed update clickhouse backup object: %w", err)
 		}
@@ -116,7 +481,7 @@ func createClickHouseBackup(ctx context.Context, rc client.Client, l logr.Logger
 
 		if bo, ok := bo.(*backoff.ExponentialBackOff); ok {
 			if time.Since(b.CreationTimestamp.Time) > bo.MaxElapsedTime {
-				b.Status.Phase = PhaseCreateFailed
+				setClickHouseBackupPhase(b, PhaseCreateFailed)
 				b.Status.Error = "backup creation timed out"
 
 				return rc.Status().Update(ctx, b)
@@ -124,29 +489,29 @@ func createClickHouseBackup(ctx context.Context, rc client.Client, l logr.Logger
 		}
 
 		op := func() error {
-			rows, err := clickhouse.GetStatus(ctx, b)
+			last, err := c.GetOperation(ctx, clickhouse.OperationCreate, b.BackupName(), b.Status.OperationID)
 			if err != nil {
 				return fmt.Errorf("failed to get backups status: %w", err)
 			}
 
-			l.V(4).Info("backup creation progress", "rows", strconv.Itoa(len(rows)))
-
-			if len(rows) > 0 {
-				last := rows[len(rows)-1]
-
+			if last != nil {
 				l.V(4).Info("backup creation progress", "status", last.Status)
 
 				switch last.Status {
-				case "error":
-					b.Status.Phase = PhaseCreateFailed
+				case clickhouse.StatusError:
+					setClickHouseBackupPhase(b, PhaseCreateFailed)
 					b.Status.Error = last.Error
 					if err := rc.Status().Update(ctx, b); err != nil {
 						return backoff.Permanent(err)
 					}
 
 					return backoff.Permanent(errors.New("clickhouse backup creating failed"))
-				case "success":
-					b.Status.Phase = "Created"
+				case clickhouse.StatusSuccess:
+					if d, err := last.Duration(); err == nil {
+						observeOperationDuration(EngineClickHouse, b, OperationCreate, d)
+					}
+
+					setClickHouseBackupPhase(b, PhaseCreated)
 					return rc.Status().Update(ctx, b)
 				default:
 					return fmt.Errorf("clickhouse backup creating operation is %q status long time", last.Status)
@@ -157,7 +522,7 @@ func createClickHouseBackup(ctx context.Context, rc client.Client, l logr.Logger
 		}
 
 		if err := backoff.Retry(op, backoff.WithContext(bo, ctx)); err != nil {
-			b.Status.Phase = PhaseCreateFailed
+			setClickHouseBackupPhase(b, PhaseCreateFailed)
 			b.Status.Error = err.Error()
 		}
 	}
@@ -165,10 +530,16 @@ func createClickHouseBackup(ctx context.Context, rc client.Client, l logr.Logger
 	return rc.Status().Update(ctx, b)
 }
 
-func uploadClickHouseBackup(ctx context.Context, rc client.Client, l logr.Logger, b *backupsv1alpha1.ClickHouseBackup) error {
-	if b.Status.Phase == "Created" {
-		if _, err := clickhouse.UploadBackup(ctx, b); err != nil {
-			b.Status.Phase = PhaseUploadFailed
+func uploadClickHouseBackup(ctx context.Context, rc client.Client, l logr.Logger, c *clickhouse.Client, b *backupsv1alpha1.ClickHouseBackup) error {
+	if b.Status.Phase == PhaseCreated {
+		ack, err := c.UploadBackup(ctx, b.BackupName(), b.Spec.UploadParams)
+		if err != nil {
+			// another operation is in progress, so uploading will be retried on next reconcile
+			if clickhouse.IsLocked(err) {
+				return err
+			}
+
+			setClickHouseBackupPhase(b, PhaseUploadFailed)
 			b.Status.Error = err.Error()
 			if err := rc.Status().Update(ctx, b); err != nil {
 				return err
@@ -177,7 +548,8 @@ func uploadClickHouseBackup(ctx context.Context, rc client.Client, l logr.Logger
 			return err
 		}
 
-		b.Status.Phase = PhaseUploading
+		setClickHouseBackupPhase(b, PhaseUploading)
+		b.Status.OperationID = ack.OperationID
 		if err := rc.Status().Update(ctx, b); err != nil {
 			return fmt.Errorf("failed update clickhouse backup object: %w", err)
 		}
@@ -196,7 +568,7 @@ func uploadClickHouseBackup(ctx context.Context, rc client.Client, l logr.Logger
 
 		if bo, ok := bo.(*backoff.ExponentialBackOff); ok {
 			if time.Since(b.CreationTimestamp.Time) > bo.MaxElapsedTime {
-				b.Status.Phase = PhaseUploadFailed
+				setClickHouseBackupPhase(b, PhaseUploadFailed)
 				b.Status.Error = "backup creation timed out"
 
 				return rc.Status().Update(ctx, b)
@@ -204,29 +576,30 @@ func uploadClickHouseBackup(ctx context.Context, rc client.Client, l logr.Logger
 		}
 
 		op := func() error {
-			rows, err := clickhouse.GetStatus(ctx, b)
+			last, err := c.GetOperation(ctx, clickhouse.OperationUpload, b.BackupName(), b.Status.OperationID)
 			if err != nil {
 				return fmt.Errorf("failed to get backups status: %w", err)
 			}
 
-			l.V(4).Info("backup uploading progress", "rows", strconv.Itoa(len(rows)))
-
-			if len(rows) > 0 {
-				last := rows[len(rows)-1]
-
+			if last != nil {
 				l.V(4).Info("backup uploading progress", "status", last.Status)
 
 				switch last.Status {
-				case "error":
-					b.Status.Phase = PhaseUploadFailed
+				case clickhouse.StatusError:
+					setClickHouseBackupPhase(b, PhaseUploadFailed)
 					b.Status.Error = last.Error
 					if err := rc.Status().Update(ctx, b); err != nil {
 						return backoff.Permanent(err)
 					}
 
 					return backoff.Permanent(errors.New("clickhouse backup uploading failed"))
-				case "success":
-					b.Status.Phase = PhaseCompleted
+				case clickhouse.StatusSuccess:
+					if d, err := last.Duration(); err == nil {
+						observeOperationDuration(EngineClickHouse, b, OperationUpload, d)
+					}
+
+					setClickHouseBackupPhase(b, PhaseCompleted)
+					b.Status.CompletionTime = &metav1.Time{Time: time.Now()}
 					return rc.Status().Update(ctx, b)
 				default:
 					return fmt.Errorf("clickhouse backup uploading operation is %q status long time", last.Status)
@@ -237,10 +610,83 @@ func uploadClickHouseBackup(ctx context.Context, rc client.Client, l logr.Logger
 		}
 
 		if err := backoff.Retry(op, backoff.WithContext(bo, ctx)); err != nil {
-			b.Status.Phase = PhaseUploadFailed
+			setClickHouseBackupPhase(b, PhaseUploadFailed)
 			b.Status.Error = err.Error()
 		}
+
+		if b.Status.Phase == PhaseCompleted {
+			if err := updateClickHouseBackupSize(ctx, c, b); err != nil {
+				l.Error(err, "failed to get uploaded backup size")
+			}
+
+			l.V(4).Info("removing uploaded backup from local storage")
+
+			if err := c.DeleteBackup(ctx, clickhouse.LocationLocal, b.BackupName()); err != nil {
+				l.Error(err, "failed to remove uploaded backup from local storage")
+			}
+		}
 	}
 
 	return rc.Status().Update(ctx, b)
 }
+
+// newClickHouseClient returns clickhouse-backup api client configured by backup spec
+func newClickHouseClient(ctx context.Context, rc client.Client, ns string, spec *backupsv1alpha1.ClickHouseBackupSpec, address, hostname string) (*clickhouse.Client, error) {
+	opts := make([]clickhouse.Option, 0)
+
+	if spec.RequestTimeout != "" {
+		d, err := time.ParseDuration(spec.RequestTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse request timeout: %w", err)
+		}
+
+		opts = append(opts, clickhouse.WithTimeout(d))
+	}
+
+	if spec.Auth != nil {
+		username, err := getSecretValue(ctx, rc, ns, spec.Auth.SecretName, getValueOrDefault(spec.Auth.UsernameKey, "username"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get api username: %w", err)
+		}
+
+		password, err := getSecretValue(ctx, rc, ns, spec.Auth.SecretName, getValueOrDefault(spec.Auth.PasswordKey, "password"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get api password: %w", err)
+		}
+
+		opts = append(opts, clickhouse.WithBasicAuth(username, password))
+	}
+
+	if spec.TLS != nil {
+		config := &tls.Config{
+			InsecureSkipVerify: spec.TLS.InsecureSkipVerify,
+			ServerName:         hostname,
+		}
+
+		var ca string
+		if spec.TLS.CASecretName != "" {
+			var err error
+			ca, err = getSecretValue(ctx, rc, ns, spec.TLS.CASecretName, getValueOrDefault(spec.TLS.CAKey, "ca.crt"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get api ca certificate: %w", err)
+			}
+
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM([]byte(ca)) {
+				return nil, errors.New("failed to parse api ca certificate")
+			}
+
+			config.RootCAs = pool
+		}
+
+		// transport is shared by clients with the same tls settings
+		key := fmt.Sprintf("%t/%s/%x", config.InsecureSkipVerify, hostname, sha256.Sum256([]byte(ca)))
+		opts = append(opts, clickhouse.WithTLSConfig(key, config))
+	}
+
+	if hostname != "" {
+		opts = append(opts, clickhouse.WithHostname(hostname))
+	}
+
+	return clickhouse.NewClient(address, opts...), nil
+}
@@ -0,0 +1,158 @@
+package factory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/clickhouse"
+	"github.com/sputnik-systems/backups-operator/internal/clickhouse/clickhousetest"
+	"github.com/sputnik-systems/backups-operator/internal/metrics"
+)
+
+// phaseTimestampClient records phase timestamp metric of backup on each status update
+type phaseTimestampClient struct {
+	client.Client
+
+	// timestamps is map of persisted phases to their timestamp metric values at update time
+	timestamps map[string]float64
+}
+
+func (c *phaseTimestampClient) Status() client.StatusWriter {
+	return &phaseTimestampStatusWriter{StatusWriter: c.Client.Status(), c: c}
+}
+
+type phaseTimestampStatusWriter struct {
+	client.StatusWriter
+	c *phaseTimestampClient
+}
+
+func (w *phaseTimestampStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if b, ok := obj.(*backupsv1alpha1.ClickHouseBackup); ok {
+		labels := withLabel(withLabel(getScheduleMetricsLabels(EngineClickHouse, b), "name", b.Name), "phase", b.Status.Phase)
+		w.c.timestamps[b.Status.Phase] = testutil.ToFloat64(metrics.BackupPhaseTimestampBySchedule.With(labels))
+	}
+
+	return w.StatusWriter.Update(ctx, obj, opts...)
+}
+
+func TestUploadClickHouseBackupObservesUploadingPhase(t *testing.T) {
+	s := clickhousetest.NewServer()
+	defer s.Close()
+
+	ctx := context.Background()
+	c := clickhouse.NewClient(s.URL)
+	if _, err := c.CreateBackup(ctx, "daily-1", nil); err != nil {
+		t.Fatalf("failed to create backup: %s", err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := backupsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	b := &backupsv1alpha1.ClickHouseBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "daily-1", Namespace: "prod", CreationTimestamp: metav1.Now()},
+		Spec:       backupsv1alpha1.ClickHouseBackupSpec{ApiAddress: s.URL},
+		Status:     backupsv1alpha1.ClickHouseBackupStatus{Phase: PhaseCreated},
+	}
+
+	rc := &phaseTimestampClient{
+		Client:     fake.NewClientBuilder().WithScheme(scheme).WithObjects(b).Build(),
+		timestamps: make(map[string]float64),
+	}
+
+	if err := uploadClickHouseBackup(ctx, rc, logr.Discard(), c, b); err != nil {
+		t.Fatalf("failed to upload backup: %s", err)
+	}
+
+	if b.Status.Phase != PhaseCompleted {
+		t.Fatalf("expected %s phase, got %s", PhaseCompleted, b.Status.Phase)
+	}
+
+	for _, phase := range []string{PhaseUploading, PhaseCompleted} {
+		if value, ok := rc.timestamps[phase]; !ok || value == 0 {
+			t.Fatalf("phase timestamp metric is not set, when backup is updated to %s phase", phase)
+		}
+	}
+}
+
+func newReadyPod(name, ip string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "prod", Labels: map[string]string{"app": "clickhouse"}},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			PodIP:      ip,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+		},
+	}
+}
+
+func TestGetClickHouseBackupDeletionAddress(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		podName  string
+		pods     []client.Object
+		address  string
+		hostname string
+	}{
+		{
+			name:     "recorded pod",
+			podName:  "clickhouse-1",
+			pods:     []client.Object{newReadyPod("clickhouse-0", "10.0.0.1"), newReadyPod("clickhouse-1", "10.0.0.2")},
+			address:  "http://10.0.0.2:7171",
+			hostname: "clickhouse.prod.svc",
+		},
+		{
+			name:     "recorded pod is gone",
+			podName:  "clickhouse-2",
+			pods:     []client.Object{newReadyPod("clickhouse-0", "10.0.0.1")},
+			address:  "http://10.0.0.1:7171",
+			hostname: "clickhouse.prod.svc",
+		},
+		{
+			name:     "no recorded pod",
+			pods:     []client.Object{newReadyPod("clickhouse-0", "10.0.0.1")},
+			address:  "http://10.0.0.1:7171",
+			hostname: "clickhouse.prod.svc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &backupsv1alpha1.ClickHouseBackup{
+				ObjectMeta: metav1.ObjectMeta{Name: "daily-1", Namespace: "prod"},
+				Spec: backupsv1alpha1.ClickHouseBackupSpec{
+					ApiAddress:  "http://clickhouse:7171",
+					PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "clickhouse"}},
+				},
+			}
+			b.Status.Api.PodName = tt.podName
+
+			rc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.pods...).Build()
+
+			address, hostname, err := getClickHouseBackupDeletionAddress(context.Background(), rc, b)
+			if err != nil {
+				t.Fatalf("failed to get deletion address: %s", err)
+			}
+
+			if address != tt.address || hostname != tt.hostname {
+				t.Fatalf("expected %s (%s) address, got %s (%s)", tt.address, tt.hostname, address, hostname)
+			}
+		})
+	}
+}
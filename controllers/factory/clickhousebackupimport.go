@@ -0,0 +1,164 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/clickhouse"
+)
+
+func ProccessClickHouseBackupImportObject(ctx context.Context, rc client.Client, l logr.Logger, bi *backupsv1alpha1.ClickHouseBackupImport) error {
+	if err := importClickHouseBackups(ctx, rc, l, bi); err != nil {
+		bi.Status.Phase = PhaseFailed
+		bi.Status.Error = err.Error()
+		bi.Status.LastScanTime = metav1.Now()
+		if err := rc.Status().Update(ctx, bi); err != nil {
+			return fmt.Errorf("failed update status: %w", err)
+		}
+
+		return err
+	}
+
+	bi.Status.Phase = PhaseCompleted
+	bi.Status.Error = ""
+	bi.Status.LastScanTime = metav1.Now()
+
+	return rc.Status().Update(ctx, bi)
+}
+
+func importClickHouseBackups(ctx context.Context, rc client.Client, l logr.Logger, bi *backupsv1alpha1.ClickHouseBackupImport) error {
+	owner, err := getClickHouseBackupImportOwner(ctx, rc, bi)
+	if err != nil {
+		return fmt.Errorf("failed to get owner schedule: %w", err)
+	}
+
+	address, err := getFQDN(bi.Spec.Backup.ApiAddress, bi.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get resource fqdn: %w", err)
+	}
+
+	c, err := newClickHouseClient(ctx, rc, bi.Namespace, &bi.Spec.Backup, address, "")
+	if err != nil {
+		return fmt.Errorf("failed to create clickhouse-backup api client: %w", err)
+	}
+
+	backups, err := c.ListBackups(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	bi.Status.Found = 0
+	bi.Status.Imported = 0
+	var imported []string
+	for _, backup := range backups {
+		if backup.Location != clickhouse.LocationRemote {
+			continue
+		}
+
+		bi.Status.Found++
+
+		name := getObjectName(backup.Name)
+
+		b := &backupsv1alpha1.ClickHouseBackup{}
+		err := rc.Get(ctx, types.NamespacedName{Name: name, Namespace: bi.Namespace}, b)
+		if err == nil {
+			// status update could fail right after object creation
+			if backupsv1alpha1.IsImported(b) && b.Status.Phase == "" {
+				b.Status.Phase = PhaseCompleted
+				if err := rc.Status().Update(ctx, b); err != nil {
+					return fmt.Errorf("failed update imported backup status: %w", err)
+				}
+			}
+
+			if backupsv1alpha1.IsImported(b) {
+				imported = append(imported, backup.Name)
+			}
+
+			continue
+		}
+
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get clickhouse backup object: %w", err)
+		}
+
+		// backup object was deleted after import, so it is not wanted anymore
+		if isContains(bi.Status.ImportedBackups, backup.Name) {
+			l.V(3).Info("skipping deleted imported backup", "name", name, "backup", backup.Name)
+
+			imported = append(imported, backup.Name)
+
+			continue
+		}
+
+		annotations := map[string]string{
+			backupsv1alpha1.ImportedAnnotation: "true",
+		}
+		if name != backup.Name {
+			annotations[backupsv1alpha1.BackupNameAnnotation] = backup.Name
+		}
+		if t, err := backup.CreatedTime(); err == nil {
+			annotations[backupsv1alpha1.CreatedAtAnnotation] = t.Format(time.RFC3339)
+		}
+		if bi.Spec.ExcludeFromRetention {
+			annotations[backupsv1alpha1.RetentionExcludedAnnotation] = "true"
+		}
+
+		// imported backups are labelled same as created by schedule
+		var labels map[string]string
+		if len(owner) > 0 {
+			labels = getScheduleBackupLabels(owner[0].Name, owner[0].UID)
+		}
+
+		b = &backupsv1alpha1.ClickHouseBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       bi.Namespace,
+				Labels:          labels,
+				Annotations:     annotations,
+				OwnerReferences: owner,
+				Finalizers:      []string{backupsv1alpha1.FinalizerName},
+			},
+			Spec: bi.Spec.Backup,
+		}
+
+		l.V(3).Info("importing backup object", "name", name, "backup", backup.Name)
+
+		if err := rc.Create(ctx, b); err != nil {
+			return fmt.Errorf("failed to create clickhouse backup object: %w", err)
+		}
+
+		b.Status.Phase = PhaseCompleted
+		if err := rc.Status().Update(ctx, b); err != nil {
+			return fmt.Errorf("failed update imported backup status: %w", err)
+		}
+
+		bi.Status.Imported++
+		imported = append(imported, backup.Name)
+	}
+
+	// remote backups are remembered only after successful scan, so deleted objects are never imported again
+	bi.Status.ImportedBackups = imported
+
+	return nil
+}
+
+func getClickHouseBackupImportOwner(ctx context.Context, rc client.Client, bi *backupsv1alpha1.ClickHouseBackupImport) ([]metav1.OwnerReference, error) {
+	if bi.Spec.ScheduleName == "" {
+		return nil, nil
+	}
+
+	bs := &backupsv1alpha1.ClickHouseBackupSchedule{}
+	if err := rc.Get(ctx, types.NamespacedName{Name: bi.Spec.ScheduleName, Namespace: bi.Namespace}, bs); err != nil {
+		return nil, err
+	}
+
+	return bs.AsOwner(), nil
+}
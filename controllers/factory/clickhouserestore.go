@@ -0,0 +1,281 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/clickhouse"
+	"github.com/sputnik-systems/backups-operator/internal/tracing"
+)
+
+func ProccessClickHouseRestoreObject(ctx context.Context, rc client.Client, l logr.Logger, r *backupsv1alpha1.ClickHouseRestore) error {
+	// trace id of last processing reconcile helps to find it in tracing backend
+	if id := tracing.TraceID(ctx); id != "" {
+		r.Status.TraceID = id
+	}
+
+	if r.Status.Phase == "" {
+		if err := startClickHouseRestore(ctx, rc, r); err != nil {
+			if errors.Is(err, ErrBackupNotCompleted) {
+				return err
+			}
+
+			r.Status.Phase = PhaseFailed
+			r.Status.Error = err.Error()
+			if err := rc.Status().Update(ctx, r); err != nil {
+				return fmt.Errorf("failed update status: %w", err)
+			}
+
+			return err
+		}
+	}
+
+	if isContains(restoreFinishedPhases, r.Status.Phase) {
+		return nil
+	}
+
+	spec, err := getClickHouseRestoreTarget(ctx, rc, r)
+	if err != nil {
+		return fmt.Errorf("failed to get restore target: %w", err)
+	}
+
+	c, err := newClickHouseClient(ctx, rc, r.Namespace, spec, r.Status.Api.Address, r.Status.Api.Hostname)
+	if err != nil {
+		return fmt.Errorf("failed to create clickhouse-backup api client: %w", err)
+	}
+
+	err = runClickHouseRestoreOperation(ctx, rc, l, c, r, clickhouse.OperationDownload, PhaseStarted, PhaseDownloading, PhaseDownloaded,
+		func() (*clickhouse.Acknowledgement, error) {
+			return c.DownloadBackup(ctx, r.Status.BackupName, r.Spec.DownloadParams)
+		})
+	if err != nil {
+		return fmt.Errorf("failed to download backup: %w", err)
+	}
+
+	err = runClickHouseRestoreOperation(ctx, rc, l, c, r, clickhouse.OperationRestore, PhaseDownloaded, PhaseRestoring, PhaseCompleted,
+		func() (*clickhouse.Acknowledgement, error) {
+			return c.RestoreBackup(ctx, r.Status.BackupName, getClickHouseRestoreParams(r))
+		})
+	if err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	if r.Status.Phase == PhaseCompleted {
+		r.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+		if err := rc.Status().Update(ctx, r); err != nil {
+			return fmt.Errorf("failed update status: %w", err)
+		}
+
+		l.V(4).Info("removing restored backup from local storage")
+
+		if err := c.DeleteBackup(ctx, clickhouse.LocationLocal, r.Status.BackupName); err != nil {
+			l.Error(err, "failed to remove restored backup from local storage")
+		}
+	}
+
+	return nil
+}
+
+// startClickHouseRestore checks restored backup and resolves target api address
+func startClickHouseRestore(ctx context.Context, rc client.Client, r *backupsv1alpha1.ClickHouseRestore) error {
+	if r.Spec.Backup.Name == "" {
+		return fmt.Errorf("backup object name is not set")
+	}
+
+	b := &backupsv1alpha1.ClickHouseBackup{}
+	nn := types.NamespacedName{Name: r.Spec.Backup.Name, Namespace: r.Spec.Backup.GetNamespace(r.Namespace)}
+	if err := rc.Get(ctx, nn, b); err != nil {
+		return fmt.Errorf("failed to get backup object: %w", err)
+	}
+
+	if err := checkRestoreSource(b, b.Status.Phase, r.Namespace); err != nil {
+		return err
+	}
+
+	spec, err := getClickHouseRestoreTarget(ctx, rc, r)
+	if err != nil {
+		return err
+	}
+
+	address, err := getFQDN(spec.ApiAddress, r.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get resource fqdn: %w", err)
+	}
+
+	if err := checkRestoreTarget(ctx, rc, r.Namespace, address, spec.PodSelector); err != nil {
+		return err
+	}
+
+	r.Status.Api.Hostname, err = getHostname(address)
+	if err != nil {
+		return fmt.Errorf("failed to get resource hostname: %w", err)
+	}
+
+	pod, err := getClickHouseBackupTargetPod(ctx, rc, r.Namespace, spec.PodSelector, address)
+	if err != nil {
+		return fmt.Errorf("failed to get target pod: %w", err)
+	}
+
+	r.Status.Api.Address = address
+
+	// api is not served by kubernetes pod, so requests are sent to given address
+	if pod != nil {
+		r.Status.Api.Address, err = getUrlWithHost(address, pod.Status.PodIP)
+		if err != nil {
+			return fmt.Errorf("failed to get pod address: %w", err)
+		}
+
+		r.Status.Api.PodName = pod.Name
+		r.Status.Api.PodUID = string(pod.UID)
+	}
+
+	r.Status.BackupName = b.BackupName()
+	r.Status.Phase = PhaseStarted
+
+	return rc.Status().Update(ctx, r)
+}
+
+// getClickHouseRestoreTarget returns clickhouse-backup api settings of restore target.
+// Backup object api settings are used, when target is not set.
+func getClickHouseRestoreTarget(ctx context.Context, rc client.Client, r *backupsv1alpha1.ClickHouseRestore) (*backupsv1alpha1.ClickHouseBackupSpec, error) {
+	if t := r.Spec.Target; t != nil {
+		return &backupsv1alpha1.ClickHouseBackupSpec{
+			ApiAddress:     t.ApiAddress,
+			PodSelector:    t.PodSelector,
+			Auth:           t.Auth,
+			TLS:            t.TLS,
+			RequestTimeout: t.RequestTimeout,
+		}, nil
+	}
+
+	// backup api and its credentials are not allowed to be used from other namespaces
+	if ns := r.Spec.Backup.GetNamespace(r.Namespace); ns != r.Namespace {
+		return nil, errors.New("target must be set for backup in other namespace")
+	}
+
+	b := &backupsv1alpha1.ClickHouseBackup{}
+	if err := rc.Get(ctx, types.NamespacedName{Name: r.Spec.Backup.Name, Namespace: r.Namespace}, b); err != nil {
+		return nil, fmt.Errorf("failed to get backup object: %w", err)
+	}
+
+	return &b.Spec, nil
+}
+
+// getClickHouseRestoreParams returns restore query params with database and table mappings
+func getClickHouseRestoreParams(r *backupsv1alpha1.ClickHouseRestore) map[string]string {
+	params := make(map[string]string)
+	for key, value := range r.Spec.RestoreParams {
+		params[key] = value
+	}
+
+	if len(r.Spec.DatabaseMapping) > 0 {
+		params["restore_database_mapping"] = getMappingParam(r.Spec.DatabaseMapping)
+	}
+
+	if len(r.Spec.TableMapping) > 0 {
+		params["restore_table_mapping"] = getMappingParam(r.Spec.TableMapping)
+	}
+
+	return params
+}
+
+// runClickHouseRestoreOperation starts operation, when restore is in start phase, and waits for its completion.
+// Restore is moved to next phase, when operation succeeds, and to failed phase otherwise.
+func runClickHouseRestoreOperation(ctx context.Context, rc client.Client, l logr.Logger, c *clickhouse.Client, r *backupsv1alpha1.ClickHouseRestore, operation, start, running, next string, f func() (*clickhouse.Acknowledgement, error)) error {
+	if r.Status.Phase == start {
+		ack, err := f()
+		if err != nil {
+			// another operation is in progress, so operation will be retried on next reconcile
+			if clickhouse.IsLocked(err) {
+				return err
+			}
+
+			r.Status.Phase = PhaseFailed
+			r.Status.Error = err.Error()
+			if err := rc.Status().Update(ctx, r); err != nil {
+				return err
+			}
+
+			return err
+		}
+
+		r.Status.Phase = running
+		r.Status.OperationID = ack.OperationID
+		if err := rc.Status().Update(ctx, r); err != nil {
+			return fmt.Errorf("failed update clickhouse restore object: %w", err)
+		}
+
+		l.V(4).Info("started restore operation", "operation", operation)
+	}
+
+	if r.Status.Phase != running {
+		return nil
+	}
+
+	l.V(4).Info("checking restore operation", "operation", operation)
+
+	var bo backoff.BackOff
+	bo, err := r.Spec.ExponentialBackOff.GetBackOff()
+	if err != nil {
+		return fmt.Errorf("failed to parse backoff settings: %w", err)
+	}
+
+	if bo, ok := bo.(*backoff.ExponentialBackOff); ok {
+		if time.Since(r.CreationTimestamp.Time) > bo.MaxElapsedTime {
+			r.Status.Phase = PhaseFailed
+			r.Status.Error = "backup restore timed out"
+
+			return rc.Status().Update(ctx, r)
+		}
+	}
+
+	op := func() error {
+		last, err := c.GetOperation(ctx, operation, r.Status.BackupName, r.Status.OperationID)
+		if err != nil {
+			return fmt.Errorf("failed to get backups status: %w", err)
+		}
+
+		if last != nil {
+			l.V(4).Info("restore operation progress", "operation", operation, "status", last.Status)
+
+			switch last.Status {
+			case clickhouse.StatusError:
+				r.Status.Phase = PhaseFailed
+				r.Status.Error = last.Error
+				if err := rc.Status().Update(ctx, r); err != nil {
+					return backoff.Permanent(err)
+				}
+
+				return backoff.Permanent(fmt.Errorf("clickhouse backup %s failed", operation))
+			case clickhouse.StatusSuccess:
+				if d, err := last.Duration(); err == nil {
+					observeOperationDuration(EngineClickHouse, r, operation, d)
+				}
+
+				r.Status.Phase = next
+				r.Status.OperationID = ""
+				return rc.Status().Update(ctx, r)
+			default:
+				return fmt.Errorf("clickhouse backup %s operation is %q status long time", operation, last.Status)
+			}
+		}
+
+		return fmt.Errorf("clickhouse backup %s operation not found", operation)
+	}
+
+	if err := backoff.Retry(op, backoff.WithContext(bo, ctx)); err != nil {
+		r.Status.Phase = PhaseFailed
+		r.Status.Error = err.Error()
+	}
+
+	return rc.Status().Update(ctx, r)
+}
@@ -0,0 +1,126 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+)
+
+// clusterScheduleTemplateData is values available in cluster backup schedule templates
+type clusterScheduleTemplateData struct {
+	Name      string
+	Namespace string
+	Labels    map[string]string
+}
+
+// ProccessClusterBackupScheduleObject creates and updates backup schedules in selected namespaces
+// and deletes them from namespaces, which are not selected anymore
+func ProccessClusterBackupScheduleObject(ctx context.Context, rc client.Client, l logr.Logger, cs *backupsv1alpha1.ClusterBackupSchedule) error {
+	namespaces, err := getSelectedNamespaces(ctx, rc, cs.Spec.NamespaceSelector)
+	if err != nil {
+		return err
+	}
+
+	selected := make(map[string]struct{})
+	var errs []error
+	for i := range namespaces {
+		ns := &namespaces[i]
+		selected[ns.Name] = struct{}{}
+
+		data := clusterScheduleTemplateData{
+			Name:      cs.Name,
+			Namespace: ns.Name,
+			Labels:    ns.Labels,
+		}
+
+		om := metav1.ObjectMeta{
+			Name:            cs.Name,
+			Namespace:       ns.Name,
+			Labels:          map[string]string{backupsv1alpha1.ClusterScheduleLabel: cs.Name},
+			OwnerReferences: cs.AsOwner(),
+		}
+
+		if cs.Spec.ClickHouse != nil {
+			if err := syncClickHouseBackupSchedule(ctx, rc, l, cs, om, cs.Spec.ClickHouse, data); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", ns.Name, err))
+			}
+		}
+
+		if cs.Spec.Dgraph != nil {
+			if err := syncDgraphBackupSchedule(ctx, rc, l, cs, om, cs.Spec.Dgraph, data); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", ns.Name, err))
+			}
+		}
+	}
+
+	// schedules are kept in selected namespaces, if their engine template is set
+	keep := func(engine string, bs metav1.Object) bool {
+		if _, ok := selected[bs.GetNamespace()]; !ok {
+			return false
+		}
+
+		return (engine == EngineClickHouse && cs.Spec.ClickHouse != nil) || (engine == EngineDgraph && cs.Spec.Dgraph != nil)
+	}
+
+	if _, err := deleteBackupSchedules(ctx, rc, l, cs, client.MatchingLabels{backupsv1alpha1.ClusterScheduleLabel: cs.Name}, keep, 0); err != nil {
+		errs = append(errs, err)
+	}
+
+	cs.Status.Namespaces = make([]string, 0, len(selected))
+	for ns := range selected {
+		cs.Status.Namespaces = append(cs.Status.Namespaces, ns)
+	}
+	sort.Strings(cs.Status.Namespaces)
+
+	cs.Status.Scheduled = len(cs.Status.Namespaces)
+	cs.Status.LastSyncTime = metav1.Now()
+	cs.Status.Error = ""
+	if len(errs) > 0 {
+		cs.Status.Error = fmt.Sprintf("%v", errs)
+	}
+
+	if err := rc.Status().Update(ctx, cs); err != nil {
+		return fmt.Errorf("failed update cluster backup schedule object: %w", err)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to sync backup schedules: %v", errs)
+	}
+
+	return nil
+}
+
+// getSelectedNamespaces returns namespaces matching selector, all namespaces are matched by nil selector
+func getSelectedNamespaces(ctx context.Context, rc client.Client, ls *metav1.LabelSelector) ([]corev1.Namespace, error) {
+	selector := labels.Everything()
+	if ls != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(ls)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse namespace selector: %w", err)
+		}
+	}
+
+	nl := &corev1.NamespaceList{}
+	if err := rc.List(ctx, nl, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	namespaces := make([]corev1.Namespace, 0, len(nl.Items))
+	for _, ns := range nl.Items {
+		// objects can't be created in terminating namespace
+		if ns.Status.Phase != corev1.NamespaceTerminating {
+			namespaces = append(namespaces, ns)
+		}
+	}
+
+	return namespaces, nil
+}
@@ -2,16 +2,17 @@ package factory
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/robfig/cron/v3"
 	"k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -20,11 +21,18 @@ const (
 	PhaseFailed       = "Failed"
 	PhaseCompleted    = "Completed"
 	PhaseCreating     = "Creating"
+	PhaseCreated      = "Created"
 	PhaseCreateFailed = "CreateFailed"
 	PhaseUploading    = "Uploading"
 	PhaseUploadFailed = "UploadFailed"
+	PhaseMissing      = "Missing"
+	PhaseDownloading  = "Downloading"
+	PhaseDownloaded   = "Downloaded"
+	PhaseRestoring    = "Restoring"
 )
 
+var invalidObjectNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
 func ScheduleTask(c *cron.Cron, l logr.Logger, schedule string, id int, f func()) (cron.EntryID, error) {
 	if id != 0 {
 		eId := cron.EntryID(id)
@@ -43,23 +51,51 @@ func ScheduleTask(c *cron.Cron, l logr.Logger, schedule string, id int, f func()
 	return c.AddFunc(schedule, f)
 }
 
-func getCredentials(ctx context.Context, rc client.Client, secrets []string, ns string) (map[string]string, error) {
-	creds := make(map[string]string)
+// RemoveTask removes scheduled task, if it exists
+func RemoveTask(c *cron.Cron, id int) {
+	if id == 0 {
+		return
+	}
 
-	for _, name := range secrets {
-		s := &v1.Secret{}
-		n := types.NamespacedName{Namespace: ns, Name: name}
-		err := rc.Get(ctx, n, s)
-		if err != nil {
-			return nil, err
+	eId := cron.EntryID(id)
+	for _, entry := range c.Entries() {
+		if entry.ID == eId {
+			c.Remove(eId)
 		}
+	}
+}
 
-		for k, v := range s.Data {
-			creds[k] = string(v)
+// getSecretValue returns value of given secret key
+func getSecretValue(ctx context.Context, rc client.Client, ns, name, key string) (string, error) {
+	s, err := getSecret(ctx, rc, ns, name)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := s.Data[key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in secret %q", key, name)
+	}
+
+	return string(value), nil
+}
+
+func getValueOrDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+
+	return value
+}
+
+func isContains(src []string, value string) bool {
+	for _, item := range src {
+		if item == value {
+			return true
 		}
 	}
 
-	return creds, nil
+	return false
 }
 
 func getFQDN(rawUrl, ns string) (string, error) {
@@ -77,22 +113,49 @@ func getFQDN(rawUrl, ns string) (string, error) {
 	return u.String(), nil
 }
 
-func getUrlWithIP(rawUrl string) (string, error) {
+// getUrlWithHost replaces url host keeping port
+func getUrlWithHost(rawUrl, host string) (string, error) {
 	u, err := url.Parse(rawUrl)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse url: %s", err)
 	}
 
-	ips, err := net.LookupIP(u.Hostname())
-	if err != nil {
-		return "", fmt.Errorf("failed to lookup ips: %s", err)
+	if host == "" {
+		return "", errors.New("empty host")
 	}
 
-	u.Host = ips[0].String() + ":" + u.Port()
+	if port := u.Port(); port != "" {
+		u.Host = net.JoinHostPort(host, port)
+	} else {
+		u.Host = host
+	}
 
 	return u.String(), nil
 }
 
+func getPodRestartCount(pod *v1.Pod) int32 {
+	var count int32
+	for _, status := range pod.Status.ContainerStatuses {
+		count += status.RestartCount
+	}
+
+	return count
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	if pod.Status.Phase != v1.PodRunning || pod.DeletionTimestamp != nil {
+		return false
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
 func getHostname(rawUrl string) (string, error) {
 	u, err := url.Parse(rawUrl)
 	if err != nil {
@@ -101,3 +164,13 @@ func getHostname(rawUrl string) (string, error) {
 
 	return u.Hostname(), nil
 }
+
+// getObjectName converts given name into valid kubernetes object name
+func getObjectName(name string) string {
+	name = invalidObjectNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	if len(name) > 253 {
+		name = name[:253]
+	}
+
+	return strings.Trim(name, ".-")
+}
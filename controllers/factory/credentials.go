@@ -0,0 +1,305 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/credentials"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// credentialsImpersonation is configuration of secrets reading on behalf of service account in backup object namespace
+var credentialsImpersonation struct {
+	config         *rest.Config
+	serviceAccount string
+
+	// clientsets is impersonated clientsets cache by namespace
+	clientsets sync.Map
+}
+
+// SetCredentialsImpersonation enables secrets reading with impersonated service account,
+// which has given name in namespace of backup object. So backup objects can read only secrets
+// allowed to this service account by namespace owners.
+func SetCredentialsImpersonation(cfg *rest.Config, serviceAccount string) {
+	credentialsImpersonation.config = cfg
+	credentialsImpersonation.serviceAccount = serviceAccount
+}
+
+// operatorCredentials is configuration of credentials providers, which use operator pod files and environment
+var operatorCredentials struct {
+	enabled   bool
+	filePaths []string
+}
+
+// SetOperatorCredentials enables environment and web identity credentials providers of operator pod
+// and allows file credentials provider to read files under given paths only.
+// Backup objects may reference only secrets of their namespace by default.
+func SetOperatorCredentials(enabled bool, filePaths []string) {
+	operatorCredentials.enabled = enabled
+	operatorCredentials.filePaths = filePaths
+}
+
+// legacy secrets credentials keys
+const (
+	secretAccessKey    = "accessKey"
+	secretSecretKey    = "secretKey"
+	secretSessionToken = "sessionToken"
+)
+
+// getSecret returns secret with operator or impersonated service account permissions
+func getSecret(ctx context.Context, rc client.Client, ns, name string) (*v1.Secret, error) {
+	// secrets are always read from backup object namespace
+	if strings.Contains(name, "/") {
+		return nil, fmt.Errorf("secret %q: cross namespace secret references are not allowed", name)
+	}
+
+	if credentialsImpersonation.serviceAccount == "" {
+		s := &v1.Secret{}
+		if err := rc.Get(ctx, types.NamespacedName{Namespace: ns, Name: name}, s); err != nil {
+			return nil, err
+		}
+
+		return s, nil
+	}
+
+	cs, err := getImpersonatedClientset(ns)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := cs.CoreV1().Secrets(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret as %s/%s service account: %w", ns, credentialsImpersonation.serviceAccount, err)
+	}
+
+	return s, nil
+}
+
+func getImpersonatedClientset(ns string) (kubernetes.Interface, error) {
+	if cs, ok := credentialsImpersonation.clientsets.Load(ns); ok {
+		return cs.(kubernetes.Interface), nil
+	}
+
+	cs, err := kubernetes.NewForConfig(getImpersonatedConfig(credentialsImpersonation.config, ns))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create impersonated client: %w", err)
+	}
+
+	credentialsImpersonation.clientsets.Store(ns, cs)
+
+	return cs, nil
+}
+
+// getImpersonatedConfig returns copy of given config, which impersonates credentials service account in given namespace
+func getImpersonatedConfig(cfg *rest.Config, ns string) *rest.Config {
+	cfg = rest.CopyConfig(cfg)
+	cfg.Impersonate = rest.ImpersonationConfig{
+		UserName: fmt.Sprintf("system:serviceaccount:%s:%s", ns, credentialsImpersonation.serviceAccount),
+	}
+
+	return cfg
+}
+
+// getStorageCredentials returns dgraph remote storage credentials from configured provider
+func getStorageCredentials(ctx context.Context, rc client.Client, bs *backupsv1alpha1.DgraphBackupSpec, ns string) (credentials.Credentials, error) {
+	if bs.Anonymous {
+		return credentials.Credentials{}, nil
+	}
+
+	p, err := getCredentialsProvider(rc, bs, ns)
+	if err != nil {
+		return credentials.Credentials{}, err
+	}
+
+	// credentials are not configured, storage defaults are used
+	if p == nil {
+		return credentials.Credentials{}, nil
+	}
+
+	return p.Retrieve(ctx)
+}
+
+func getCredentialsProvider(rc client.Client, bs *backupsv1alpha1.DgraphBackupSpec, ns string) (credentials.Provider, error) {
+	c := bs.Credentials
+	if c == nil {
+		if len(bs.Secrets) == 0 {
+			return nil, nil
+		}
+
+		return &legacySecretsProvider{rc: rc, namespace: ns, secrets: bs.Secrets}, nil
+	}
+
+	providers := make([]credentials.Provider, 0)
+	if c.Secret != nil {
+		providers = append(providers, &secretProvider{rc: rc, namespace: ns, spec: c.Secret})
+	}
+
+	if c.File != nil {
+		for _, path := range []string{c.File.AccessKeyPath, c.File.SecretKeyPath, c.File.SessionTokenPath} {
+			if path != "" && !isAllowedCredentialsFile(path) {
+				return nil, fmt.Errorf("credentials file %q is not allowed by operator", path)
+			}
+		}
+
+		providers = append(providers, &credentials.File{
+			AccessKeyPath:    c.File.AccessKeyPath,
+			SecretKeyPath:    c.File.SecretKeyPath,
+			SessionTokenPath: c.File.SessionTokenPath,
+		})
+	}
+
+	if c.WebIdentity != nil {
+		if !operatorCredentials.enabled {
+			return nil, errors.New("web identity credentials provider is disabled by operator")
+		}
+
+		if c.WebIdentity.TokenFile != "" && !isAllowedCredentialsFile(c.WebIdentity.TokenFile) {
+			return nil, fmt.Errorf("web identity token file %q is not allowed by operator", c.WebIdentity.TokenFile)
+		}
+
+		providers = append(providers, &credentials.WebIdentity{
+			RoleARN:     c.WebIdentity.RoleARN,
+			TokenFile:   c.WebIdentity.TokenFile,
+			SessionName: c.WebIdentity.SessionName,
+			Region:      bs.Region,
+		})
+	}
+
+	if c.Environment {
+		if !operatorCredentials.enabled {
+			return nil, errors.New("environment credentials provider is disabled by operator")
+		}
+
+		providers = append(providers, &credentials.Env{})
+	}
+
+	switch len(providers) {
+	case 0:
+		return nil, errors.New("credentials provider is not set")
+	case 1:
+		return providers[0], nil
+	default:
+		return nil, errors.New("only one credentials provider may be set")
+	}
+}
+
+// isAllowedCredentialsFile checks if file is under one of operator allowed credentials paths
+func isAllowedCredentialsFile(path string) bool {
+	if !filepath.IsAbs(path) {
+		return false
+	}
+
+	path = filepath.Clean(path)
+	for _, allowed := range operatorCredentials.filePaths {
+		allowed = filepath.Clean(allowed)
+		if path == allowed || strings.HasPrefix(path, strings.TrimSuffix(allowed, "/")+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// secretProvider reads credentials from typed secret key references
+type secretProvider struct {
+	rc        client.Client
+	namespace string
+	spec      *backupsv1alpha1.StorageCredentialsSecret
+}
+
+func (p *secretProvider) Retrieve(ctx context.Context) (credentials.Credentials, error) {
+	var creds credentials.Credentials
+	var err error
+
+	if creds.AccessKey, err = getSecretKeySelectorValue(ctx, p.rc, p.namespace, &p.spec.AccessKey); err != nil {
+		return creds, fmt.Errorf("failed to get access key: %w", err)
+	}
+
+	if creds.SecretKey, err = getSecretKeySelectorValue(ctx, p.rc, p.namespace, &p.spec.SecretKey); err != nil {
+		return creds, fmt.Errorf("failed to get secret key: %w", err)
+	}
+
+	if p.spec.SessionToken != nil {
+		if creds.SessionToken, err = getSecretKeySelectorValue(ctx, p.rc, p.namespace, p.spec.SessionToken); err != nil {
+			return creds, fmt.Errorf("failed to get session token: %w", err)
+		}
+	}
+
+	return creds, nil
+}
+
+// legacySecretsProvider reads credentials from well known keys of listed secrets
+type legacySecretsProvider struct {
+	rc        client.Client
+	namespace string
+	secrets   []string
+}
+
+func (p *legacySecretsProvider) Retrieve(ctx context.Context) (credentials.Credentials, error) {
+	values := make(map[string]string)
+	sources := make(map[string]string)
+
+	for _, name := range p.secrets {
+		s, err := getSecret(ctx, p.rc, p.namespace, name)
+		if err != nil {
+			return credentials.Credentials{}, err
+		}
+
+		for _, key := range []string{secretAccessKey, secretSecretKey, secretSessionToken} {
+			value, ok := s.Data[key]
+			if !ok {
+				continue
+			}
+
+			if source, ok := sources[key]; ok {
+				return credentials.Credentials{}, fmt.Errorf("key %q is set in both %q and %q secrets", key, source, name)
+			}
+
+			values[key] = string(value)
+			sources[key] = name
+		}
+	}
+
+	for _, key := range []string{secretAccessKey, secretSecretKey} {
+		if _, ok := values[key]; !ok {
+			return credentials.Credentials{}, fmt.Errorf("key %q not found in secrets %q", key, strings.Join(p.secrets, ", "))
+		}
+	}
+
+	return credentials.Credentials{
+		AccessKey:    values[secretAccessKey],
+		SecretKey:    values[secretSecretKey],
+		SessionToken: values[secretSessionToken],
+	}, nil
+}
+
+func getSecretKeySelectorValue(ctx context.Context, rc client.Client, ns string, sel *v1.SecretKeySelector) (string, error) {
+	optional := sel.Optional != nil && *sel.Optional
+
+	s, err := getSecret(ctx, rc, ns, sel.Name)
+	if err != nil {
+		if optional && apierrors.IsNotFound(err) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	value, ok := s.Data[sel.Key]
+	if !ok && !optional {
+		return "", fmt.Errorf("key %q not found in secret %q", sel.Key, sel.Name)
+	}
+
+	return string(value), nil
+}
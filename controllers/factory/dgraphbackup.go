@@ -3,15 +3,29 @@ package factory
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
 	"github.com/sputnik-systems/backups-operator/controllers/factory/finalize"
 	"github.com/sputnik-systems/backups-operator/internal/dgraph"
+	"github.com/sputnik-systems/backups-operator/internal/tracing"
 )
 
-func ProccessDgraphBackupObject(ctx context.Context, rc client.Client, b *backupsv1alpha1.DgraphBackup) error {
+func ProccessDgraphBackupObject(ctx context.Context, rc client.Client, l logr.Logger, b *backupsv1alpha1.DgraphBackup) error {
+	// imported backup status is set by import controller
+	if backupsv1alpha1.IsImported(b) {
+		return nil
+	}
+
+	// trace id of last processing reconcile helps to find it in tracing backend
+	if id := tracing.TraceID(ctx); id != "" {
+		b.Status.TraceID = id
+	}
+
 	if b.Status.Phase == "" {
 		if err := finalize.AddFinalizer(ctx, rc, b); err != nil {
 			return fmt.Errorf("failed to add finalizer: %w", err)
@@ -24,21 +38,37 @@ func ProccessDgraphBackupObject(ctx context.Context, rc client.Client, b *backup
 	}
 
 	if b.Status.Phase == PhaseStarted {
-		if err := createDgraphBackup(ctx, rc, b); err != nil {
+		if err := processBackupHooks(ctx, rc, l, b, b.Spec.Hooks, backupsv1alpha1.BackupHookStagePre, &b.Status.Phase, &b.Status.Error, &b.Status.Hooks, ""); err != nil {
+			return fmt.Errorf("failed to run pre hooks: %w", err)
+		}
+	}
+
+	if b.Status.Phase == PhaseStarted {
+		if err := createDgraphBackup(ctx, rc, l, b); err != nil {
 			return fmt.Errorf("failed to create backup: %w", err)
 		}
 
 		b.Status.Phase = PhaseCompleted
+		b.Status.CompletionTime = &metav1.Time{Time: time.Now()}
 		if err := rc.Status().Update(ctx, b); err != nil {
 			return fmt.Errorf("failed update status: %w", err)
 		}
 	}
 
+	if isContains(hookFinishedPhases, b.Status.Phase) {
+		if err := processBackupHooks(ctx, rc, l, b, b.Spec.Hooks, backupsv1alpha1.BackupHookStagePost, &b.Status.Phase, &b.Status.Error, &b.Status.Hooks, ""); err != nil {
+			return fmt.Errorf("failed to run post hooks: %w", err)
+		}
+	}
+
 	return nil
 }
 
-func DeleteDgraphBackupObject(ctx context.Context, rc client.Client, b *backupsv1alpha1.DgraphBackup) error {
-	creds, err := getCredentials(ctx, rc, b.Spec.Secrets, b.Namespace)
+func DeleteDgraphBackupObject(ctx context.Context, rc client.Client, b *backupsv1alpha1.DgraphBackup) (err error) {
+	ctx, span := tracing.Start(ctx, "DgraphBackup.Finalize", tracing.Object(b.Name, b.Namespace)...)
+	defer func() { tracing.End(span, err) }()
+
+	creds, err := getStorageCredentials(ctx, rc, &b.Spec, b.Namespace)
 	if err != nil {
 		return fmt.Errorf("failed to get creds: %w", err)
 	}
@@ -54,10 +84,18 @@ func DeleteDgraphBackupObject(ctx context.Context, rc client.Client, b *backupsv
 	return nil
 }
 
-func createDgraphBackup(ctx context.Context, rc client.Client, b *backupsv1alpha1.DgraphBackup) error {
-	creds, err := getCredentials(ctx, rc, b.Spec.Secrets, b.Namespace)
+func createDgraphBackup(ctx context.Context, rc client.Client, l logr.Logger, b *backupsv1alpha1.DgraphBackup) error {
+	creds, err := getStorageCredentials(ctx, rc, &b.Spec, b.Namespace)
 	if err != nil {
-		return fmt.Errorf("failed to get dgraph export creds: %w", err)
+		err = fmt.Errorf("failed to get dgraph export creds: %w", err)
+
+		b.Status.Phase = PhaseFailed
+		b.Status.Error = err.Error()
+		if err := rc.Status().Update(ctx, b); err != nil {
+			return fmt.Errorf("failed to update dgraph backup object status: %w", err)
+		}
+
+		return err
 	}
 
 	b.Spec.AdminUrl, err = getFQDN(b.Spec.AdminUrl, b.Namespace)
@@ -65,9 +103,12 @@ func createDgraphBackup(ctx context.Context, rc client.Client, b *backupsv1alpha
 		return fmt.Errorf("failed to get fqdn: %w", err)
 	}
 
+	start := time.Now()
+
 	out, err := dgraph.Export(ctx, rc, &b.Spec, creds)
 	if err != nil {
 		b.Status.Phase = PhaseFailed
+		b.Status.Error = err.Error()
 		if err := rc.Status().Update(ctx, b); err != nil {
 			return fmt.Errorf("failed to update dgraph backup object status: %w", err)
 		}
@@ -75,6 +116,8 @@ func createDgraphBackup(ctx context.Context, rc client.Client, b *backupsv1alpha
 		return err
 	}
 
+	observeOperationDuration(EngineDgraph, b, OperationCreate, time.Since(start))
+
 	files := make([]string, 0)
 	for _, file := range out.ExportedFiles {
 		files = append(files, string(file))
@@ -84,5 +127,13 @@ func createDgraphBackup(ctx context.Context, rc client.Client, b *backupsv1alpha
 	b.Status.ExportResponse.Message = string(out.Response.Message)
 	b.Status.ExportResponse.Code = string(out.Response.Code)
 
+	// export is completed anyway, size is only informational
+	if size, err := dgraph.GetExportSize(ctx, b, creds); err != nil {
+		l.Error(err, "failed to get dgraph export size")
+	} else {
+		b.Status.Size = size
+		observeBackupSize(EngineDgraph, b, size)
+	}
+
 	return rc.Status().Update(ctx, b)
 }
@@ -0,0 +1,152 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/dgraph"
+)
+
+func ProccessDgraphBackupImportObject(ctx context.Context, rc client.Client, l logr.Logger, bi *backupsv1alpha1.DgraphBackupImport) error {
+	if err := importDgraphBackups(ctx, rc, l, bi); err != nil {
+		bi.Status.Phase = PhaseFailed
+		bi.Status.Error = err.Error()
+		bi.Status.LastScanTime = metav1.Now()
+		if err := rc.Status().Update(ctx, bi); err != nil {
+			return fmt.Errorf("failed update status: %w", err)
+		}
+
+		return err
+	}
+
+	bi.Status.Phase = PhaseCompleted
+	bi.Status.Error = ""
+	bi.Status.LastScanTime = metav1.Now()
+
+	return rc.Status().Update(ctx, bi)
+}
+
+func importDgraphBackups(ctx context.Context, rc client.Client, l logr.Logger, bi *backupsv1alpha1.DgraphBackupImport) error {
+	owner, err := getDgraphBackupImportOwner(ctx, rc, bi)
+	if err != nil {
+		return fmt.Errorf("failed to get owner schedule: %w", err)
+	}
+
+	creds, err := getStorageCredentials(ctx, rc, &bi.Spec.Backup, bi.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get creds: %w", err)
+	}
+
+	exports, err := dgraph.ListExports(ctx, &bi.Spec.Backup, creds)
+	if err != nil {
+		return fmt.Errorf("failed to list exports: %w", err)
+	}
+
+	bi.Status.Found = len(exports)
+	bi.Status.Imported = 0
+	var imported []string
+	for _, export := range exports {
+		name := getObjectName(export.Name)
+
+		b := &backupsv1alpha1.DgraphBackup{}
+		err := rc.Get(ctx, types.NamespacedName{Name: name, Namespace: bi.Namespace}, b)
+		if err == nil {
+			// status update could fail right after object creation
+			if backupsv1alpha1.IsImported(b) && b.Status.Phase == "" {
+				b.Status.Phase = PhaseCompleted
+				b.Status.ExportResponse.ExportedFiles = export.Files
+				b.Status.Size = export.Size
+				if err := rc.Status().Update(ctx, b); err != nil {
+					return fmt.Errorf("failed update imported backup status: %w", err)
+				}
+			}
+
+			if backupsv1alpha1.IsImported(b) {
+				imported = append(imported, export.Name)
+			}
+
+			continue
+		}
+
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get dgraph backup object: %w", err)
+		}
+
+		// backup object was deleted after import, so it is not wanted anymore
+		if isContains(bi.Status.ImportedBackups, export.Name) {
+			l.V(3).Info("skipping deleted imported backup", "name", name, "export", export.Name)
+
+			imported = append(imported, export.Name)
+
+			continue
+		}
+
+		// imported backups are labelled same as created by schedule
+		var labels map[string]string
+		if len(owner) > 0 {
+			labels = getScheduleBackupLabels(owner[0].Name, owner[0].UID)
+		}
+
+		annotations := map[string]string{
+			backupsv1alpha1.ImportedAnnotation:  "true",
+			backupsv1alpha1.CreatedAtAnnotation: export.ModTime.Format(time.RFC3339),
+		}
+		if bi.Spec.ExcludeFromRetention {
+			annotations[backupsv1alpha1.RetentionExcludedAnnotation] = "true"
+		}
+
+		b = &backupsv1alpha1.DgraphBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       bi.Namespace,
+				Labels:          labels,
+				Annotations:     annotations,
+				OwnerReferences: owner,
+				Finalizers:      []string{backupsv1alpha1.FinalizerName},
+			},
+			Spec: bi.Spec.Backup,
+		}
+
+		l.V(3).Info("importing backup object", "name", name, "export", export.Name)
+
+		if err := rc.Create(ctx, b); err != nil {
+			return fmt.Errorf("failed to create dgraph backup object: %w", err)
+		}
+
+		b.Status.Phase = PhaseCompleted
+		b.Status.ExportResponse.ExportedFiles = export.Files
+		b.Status.Size = export.Size
+		if err := rc.Status().Update(ctx, b); err != nil {
+			return fmt.Errorf("failed update imported backup status: %w", err)
+		}
+
+		bi.Status.Imported++
+		imported = append(imported, export.Name)
+	}
+
+	// remote backups are remembered only after successful scan, so deleted objects are never imported again
+	bi.Status.ImportedBackups = imported
+
+	return nil
+}
+
+func getDgraphBackupImportOwner(ctx context.Context, rc client.Client, bi *backupsv1alpha1.DgraphBackupImport) ([]metav1.OwnerReference, error) {
+	if bi.Spec.ScheduleName == "" {
+		return nil, nil
+	}
+
+	bs := &backupsv1alpha1.DgraphBackupSchedule{}
+	if err := rc.Get(ctx, types.NamespacedName{Name: bi.Spec.ScheduleName, Namespace: bi.Namespace}, bs); err != nil {
+		return nil, err
+	}
+
+	return bs.AsOwner(), nil
+}
@@ -0,0 +1,284 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/credentials"
+	"github.com/sputnik-systems/backups-operator/internal/tracing"
+)
+
+// defaultDgraphImage is dgraph image used by live loader job, when it is not set in spec
+const defaultDgraphImage = "dgraph/dgraph:v21.03.2"
+
+func ProccessDgraphRestoreObject(ctx context.Context, rc client.Client, l logr.Logger, r *backupsv1alpha1.DgraphRestore) error {
+	// trace id of last processing reconcile helps to find it in tracing backend
+	if id := tracing.TraceID(ctx); id != "" {
+		r.Status.TraceID = id
+	}
+
+	if r.Status.Phase == "" {
+		if err := startDgraphRestore(ctx, rc, l, r); err != nil {
+			if errors.Is(err, ErrBackupNotCompleted) {
+				return err
+			}
+
+			r.Status.Phase = PhaseFailed
+			r.Status.Error = err.Error()
+			if err := rc.Status().Update(ctx, r); err != nil {
+				return fmt.Errorf("failed update status: %w", err)
+			}
+
+			return err
+		}
+	}
+
+	if r.Status.Phase == PhaseRestoring {
+		if err := checkDgraphRestoreJob(ctx, rc, l, r); err != nil {
+			return fmt.Errorf("failed to check live loader job: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// startDgraphRestore creates live loader job, which loads backup export files into target alpha
+func startDgraphRestore(ctx context.Context, rc client.Client, l logr.Logger, r *backupsv1alpha1.DgraphRestore) error {
+	if r.Spec.Backup.Name == "" {
+		return fmt.Errorf("backup object name is not set")
+	}
+
+	b := &backupsv1alpha1.DgraphBackup{}
+	nn := types.NamespacedName{Name: r.Spec.Backup.Name, Namespace: r.Spec.Backup.GetNamespace(r.Namespace)}
+	if err := rc.Get(ctx, nn, b); err != nil {
+		return fmt.Errorf("failed to get backup object: %w", err)
+	}
+
+	if err := checkRestoreSource(b, b.Status.Phase, r.Namespace); err != nil {
+		return err
+	}
+
+	creds, err := getDgraphRestoreCredentials(ctx, rc, r, b)
+	if err != nil {
+		return fmt.Errorf("failed to get creds: %w", err)
+	}
+
+	job, err := newDgraphRestoreJob(r, b, creds)
+	if err != nil {
+		return err
+	}
+
+	if err := controllerutil.SetControllerReference(r, job, rc.Scheme()); err != nil {
+		return fmt.Errorf("failed to set job owner: %w", err)
+	}
+
+	if creds.AccessKey != "" {
+		secret := newDgraphRestoreSecret(job.Name, r.Namespace, creds)
+		if err := controllerutil.SetControllerReference(r, secret, rc.Scheme()); err != nil {
+			return fmt.Errorf("failed to set secret owner: %w", err)
+		}
+
+		if err := rc.Create(ctx, secret); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create credentials secret: %w", err)
+		}
+	}
+
+	if err := rc.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create live loader job: %w", err)
+	}
+
+	l.V(4).Info("started live loader job", "job", job.Name)
+
+	r.Status.Phase = PhaseRestoring
+	r.Status.JobName = job.Name
+
+	return rc.Status().Update(ctx, r)
+}
+
+// getDgraphRestoreCredentials returns remote storage credentials of restore object.
+// Backup object credentials are used, when restore credentials are not set.
+// Credentials are written to secret in restore namespace, so only secrets of this namespace may be their source.
+func getDgraphRestoreCredentials(ctx context.Context, rc client.Client, r *backupsv1alpha1.DgraphRestore, b *backupsv1alpha1.DgraphBackup) (credentials.Credentials, error) {
+	if r.Spec.Credentials != nil || r.Spec.Anonymous {
+		if err := checkRestoreCredentials(r.Spec.Credentials); err != nil {
+			return credentials.Credentials{}, err
+		}
+
+		return getStorageCredentials(ctx, rc, &backupsv1alpha1.DgraphBackupSpec{Credentials: r.Spec.Credentials, Anonymous: r.Spec.Anonymous}, r.Namespace)
+	}
+
+	// backup credentials are not allowed to be used from other namespaces
+	if b.Namespace != r.Namespace {
+		return credentials.Credentials{}, errors.New("credentials must be set for backup in other namespace")
+	}
+
+	if err := checkRestoreCredentials(b.Spec.Credentials); err != nil {
+		return credentials.Credentials{}, err
+	}
+
+	return getStorageCredentials(ctx, rc, &b.Spec, b.Namespace)
+}
+
+// checkRestoreCredentials checks that credentials are read from secrets,
+// operator pod credentials must not be exposed to restore namespace
+func checkRestoreCredentials(c *backupsv1alpha1.StorageCredentials) error {
+	if c == nil {
+		return nil
+	}
+
+	if c.File != nil || c.WebIdentity != nil || c.Environment {
+		return errors.New("only secret credentials may be used for restore, because they are passed to job through secret in restore namespace")
+	}
+
+	return nil
+}
+
+// newDgraphRestoreJob returns live loader job, which loads export files from backup destination
+func newDgraphRestoreJob(r *backupsv1alpha1.DgraphRestore, b *backupsv1alpha1.DgraphBackup, creds credentials.Credentials) (*batchv1.Job, error) {
+	destination := strings.TrimSuffix(b.Spec.Destination, "/")
+
+	var files []string
+	var schema string
+	for _, file := range b.Status.ExportResponse.ExportedFiles {
+		switch {
+		case strings.HasSuffix(file, ".rdf.gz"), strings.HasSuffix(file, ".json.gz"):
+			files = append(files, destination+"/"+file)
+		case strings.HasSuffix(file, ".schema.gz") && schema == "":
+			schema = destination + "/" + file
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, errors.New("backup export files not found")
+	}
+
+	command := []string{
+		"dgraph", "live",
+		"--files", strings.Join(files, ","),
+		"--alpha", r.Spec.AlphaAddress,
+		"--zero", r.Spec.ZeroAddress,
+	}
+
+	if schema != "" {
+		command = append(command, "--schema", schema)
+	}
+
+	container := v1.Container{
+		Name:    "live",
+		Image:   getValueOrDefault(r.Spec.Image, defaultDgraphImage),
+		Command: command,
+	}
+
+	if region := getValueOrDefault(r.Spec.Region, b.Spec.Region); region != "" {
+		container.Env = append(container.Env, v1.EnvVar{Name: "AWS_REGION", Value: region})
+	}
+
+	name := getObjectName(r.Name + "-restore")
+
+	// credentials are passed through secret, so they are not visible in job spec
+	if creds.AccessKey != "" {
+		container.EnvFrom = append(container.EnvFrom, v1.EnvFromSource{
+			SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: name}},
+		})
+	}
+
+	// live loading is not idempotent, so failed job is not retried
+	backoffLimit := int32(0)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: r.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					RestartPolicy: v1.RestartPolicyNever,
+					Containers:    []v1.Container{container},
+				},
+			},
+		},
+	}, nil
+}
+
+// newDgraphRestoreSecret returns secret with live loader s3 and minio credentials environment variables
+func newDgraphRestoreSecret(name, ns string, creds credentials.Credentials) *v1.Secret {
+	data := map[string]string{
+		"AWS_ACCESS_KEY_ID":     creds.AccessKey,
+		"AWS_SECRET_ACCESS_KEY": creds.SecretKey,
+		"MINIO_ACCESS_KEY":      creds.AccessKey,
+		"MINIO_SECRET_KEY":      creds.SecretKey,
+	}
+
+	if creds.SessionToken != "" {
+		data["AWS_SESSION_TOKEN"] = creds.SessionToken
+	}
+
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+		},
+		StringData: data,
+	}
+}
+
+// checkDgraphRestoreJob updates restore phase by live loader job conditions
+func checkDgraphRestoreJob(ctx context.Context, rc client.Client, l logr.Logger, r *backupsv1alpha1.DgraphRestore) error {
+	job := &batchv1.Job{}
+	err := rc.Get(ctx, types.NamespacedName{Name: r.Status.JobName, Namespace: r.Namespace}, job)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+
+	if apierrors.IsNotFound(err) {
+		r.Status.Phase = PhaseFailed
+		r.Status.Error = fmt.Sprintf("job %s not found", r.Status.JobName)
+
+		return rc.Status().Update(ctx, r)
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != v1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case batchv1.JobComplete:
+			l.V(4).Info("live loader job completed", "job", job.Name)
+
+			if job.Status.StartTime != nil && job.Status.CompletionTime != nil {
+				observeOperationDuration(EngineDgraph, r, OperationRestore, job.Status.CompletionTime.Sub(job.Status.StartTime.Time))
+			}
+
+			r.Status.Phase = PhaseCompleted
+			r.Status.CompletionTime = job.Status.CompletionTime
+			if r.Status.CompletionTime == nil {
+				r.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+			}
+
+			return rc.Status().Update(ctx, r)
+		case batchv1.JobFailed:
+			r.Status.Phase = PhaseFailed
+			r.Status.Error = fmt.Sprintf("job %s failed: %s", job.Name, condition.Message)
+
+			return rc.Status().Update(ctx, r)
+		}
+	}
+
+	return nil
+}
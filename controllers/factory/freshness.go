@@ -0,0 +1,172 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/metrics"
+	"github.com/sputnik-systems/backups-operator/internal/notify"
+)
+
+// FreshnessCheckSchedule is backups freshness check task schedule
+const FreshnessCheckSchedule = "@every 5m"
+
+// CheckClickHouseBackupsFreshness compares newest completed schedule backup age with max backup age
+func CheckClickHouseBackupsFreshness(ctx context.Context, rc client.Client, l logr.Logger, n notify.Notifier, key types.NamespacedName) error {
+	bs := &backupsv1alpha1.ClickHouseBackupSchedule{}
+	if err := rc.Get(ctx, key, bs); err != nil {
+		return fmt.Errorf("failed to get clickhouse backup schedule object: %w", err)
+	}
+
+	bl := &backupsv1alpha1.ClickHouseBackupList{}
+	if err := rc.List(ctx, bl, client.InNamespace(bs.Namespace), client.MatchingFields{OwnerUIDField: string(bs.UID)}); err != nil {
+		return fmt.Errorf("failed to list clickhouse backup objects: %w", err)
+	}
+
+	var newest *metav1.Time
+	for i := range bl.Items {
+		b := &bl.Items[i]
+
+		if b.Status.Phase == PhaseCompleted {
+			newest = getNewerTime(newest, backupsv1alpha1.GetCreationTime(b))
+		}
+	}
+
+	changed, err := updateFreshness(ctx, l, n, EngineClickHouse, bs, bs.Spec.MaxBackupAge, newest, &bs.Status.LastCompletedTime, &bs.Status.Conditions)
+	if err != nil || !changed {
+		return err
+	}
+
+	if err := rc.Status().Update(ctx, bs); err != nil {
+		return fmt.Errorf("failed update clickhouse backup schedule object: %w", err)
+	}
+
+	return nil
+}
+
+// CheckDgraphBackupsFreshness compares newest completed schedule backup age with max backup age
+func CheckDgraphBackupsFreshness(ctx context.Context, rc client.Client, l logr.Logger, n notify.Notifier, key types.NamespacedName) error {
+	bs := &backupsv1alpha1.DgraphBackupSchedule{}
+	if err := rc.Get(ctx, key, bs); err != nil {
+		return fmt.Errorf("failed to get dgraph backup schedule object: %w", err)
+	}
+
+	bl := &backupsv1alpha1.DgraphBackupList{}
+	if err := rc.List(ctx, bl, client.InNamespace(bs.Namespace), client.MatchingFields{OwnerUIDField: string(bs.UID)}); err != nil {
+		return fmt.Errorf("failed to list dgraph backup objects: %w", err)
+	}
+
+	var newest *metav1.Time
+	for i := range bl.Items {
+		b := &bl.Items[i]
+
+		if b.Status.Phase == PhaseCompleted {
+			newest = getNewerTime(newest, backupsv1alpha1.GetCreationTime(b))
+		}
+	}
+
+	changed, err := updateFreshness(ctx, l, n, EngineDgraph, bs, bs.Spec.MaxBackupAge, newest, &bs.Status.LastCompletedTime, &bs.Status.Conditions)
+	if err != nil || !changed {
+		return err
+	}
+
+	if err := rc.Status().Update(ctx, bs); err != nil {
+		return fmt.Errorf("failed update dgraph backup schedule object: %w", err)
+	}
+
+	return nil
+}
+
+// updateFreshness sets fresh condition and metrics of schedule.
+// Schedule without completed backups is stale, when it is older than max backup age.
+// Notification is sent only on condition status change.
+func updateFreshness(ctx context.Context, l logr.Logger, n notify.Notifier, engine string, obj metav1.Object, maxBackupAge string, newest *metav1.Time, lastCompleted **metav1.Time, conditions *[]metav1.Condition) (bool, error) {
+	maxAge, err := time.ParseDuration(maxBackupAge)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse max backup age: %w", err)
+	}
+
+	labels := prometheus.Labels{
+		"engine":    engine,
+		"schedule":  obj.GetName(),
+		"namespace": obj.GetNamespace(),
+	}
+
+	condition := metav1.Condition{
+		Type:   backupsv1alpha1.ConditionFresh,
+		Status: metav1.ConditionTrue,
+		Reason: backupsv1alpha1.ReasonFresh,
+	}
+
+	since := obj.GetCreationTimestamp().Time
+	if newest != nil {
+		since = newest.Time
+	}
+
+	age := time.Since(since)
+	switch {
+	case age > maxAge && newest == nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = backupsv1alpha1.ReasonStale
+		condition.Message = fmt.Sprintf("no completed backups for more than %s", maxAge)
+	case age > maxAge:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = backupsv1alpha1.ReasonStale
+		condition.Message = fmt.Sprintf("newest completed backup is older than %s", maxAge)
+	case newest == nil:
+		condition.Message = "no completed backups yet"
+	default:
+		condition.Message = fmt.Sprintf("newest completed backup is younger than %s", maxAge)
+	}
+
+	stale := condition.Status == metav1.ConditionFalse
+	if stale {
+		metrics.StaleBySchedule.With(labels).Set(1)
+	} else {
+		metrics.StaleBySchedule.With(labels).Set(0)
+	}
+
+	previous := meta.FindStatusCondition(*conditions, condition.Type)
+	transition := previous == nil || previous.Status != condition.Status
+	changed := transition || !newest.Equal(*lastCompleted) || previous.Message != condition.Message
+
+	*lastCompleted = newest
+	meta.SetStatusCondition(conditions, condition)
+
+	// initially fresh schedule is not worth notification
+	if transition && (stale || previous != nil) && n != nil {
+		l.V(3).Info("schedule freshness changed", "reason", condition.Reason)
+
+		e := notify.Event{
+			Reason:    condition.Reason,
+			Engine:    engine,
+			Schedule:  obj.GetName(),
+			Namespace: obj.GetNamespace(),
+			Message:   condition.Message,
+			Time:      time.Now(),
+		}
+
+		if err := n.Notify(ctx, e); err != nil {
+			l.Error(err, "failed to send notification")
+		}
+	}
+
+	return changed, nil
+}
+
+func getNewerTime(current *metav1.Time, t time.Time) *metav1.Time {
+	if current == nil || t.After(current.Time) {
+		return &metav1.Time{Time: t}
+	}
+
+	return current
+}
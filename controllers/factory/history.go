@@ -0,0 +1,226 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/clickhouse"
+	"github.com/sputnik-systems/backups-operator/internal/dgraph"
+)
+
+// recentRunsLimit is max length of schedule recent runs list
+const recentRunsLimit = 10
+
+// remoteBackupPhases is list of clickhouse backup phases, when backup may exist in remote storage
+var remoteBackupPhases = []string{PhaseUploading, PhaseUploadFailed, PhaseCompleted, PhaseMissing}
+
+// finishedPhases is list of phases, which backup object never leaves by itself
+var finishedPhases = []string{PhaseCompleted, PhaseFailed, PhaseCreateFailed, PhaseUploadFailed, PhaseMissing}
+
+// scheduleBackup is engine independent backup object info used for schedule history
+type scheduleBackup struct {
+	obj            client.Object
+	phase          string
+	size           int64
+	completionTime *metav1.Time
+}
+
+// UpdateClickHouseScheduleHistory sets schedule history status fields, returns true if they are changed
+func UpdateClickHouseScheduleHistory(ctx context.Context, rc client.Client, bs *backupsv1alpha1.ClickHouseBackupSchedule) (bool, error) {
+	bl := &backupsv1alpha1.ClickHouseBackupList{}
+	if err := rc.List(ctx, bl, client.InNamespace(bs.Namespace), client.MatchingFields{OwnerUIDField: string(bs.UID)}); err != nil {
+		return false, fmt.Errorf("failed to list clickhouse backup objects: %w", err)
+	}
+
+	backups := make([]scheduleBackup, 0)
+	var deletable, retained []string
+	for i := range bl.Items {
+		b := &bl.Items[i]
+
+		backups = append(backups, scheduleBackup{
+			obj:            b,
+			phase:          b.Status.Phase,
+			size:           b.Status.Size,
+			completionTime: b.Status.CompletionTime,
+		})
+
+		if !isContains(remoteBackupPhases, b.Status.Phase) {
+			continue
+		}
+
+		// imported backups were not created by schedule, so they are never deleted by orphans audit
+		if !backupsv1alpha1.IsImported(b) && isContains(getClickHouseBackupDeletionLocations(b), clickhouse.LocationRemote) {
+			deletable = append(deletable, b.BackupName())
+		} else {
+			retained = append(retained, b.BackupName())
+		}
+	}
+
+	history := getScheduleHistory(backups, getNextScheduleTime(bs.Spec.Suspend, bs.Spec.Schedule, bs.Spec.TimeZone, bs.Spec.Jitter, bs))
+	ObserveLastSuccess(EngineClickHouse, bs, history.LastSuccessfulTime)
+
+	remote := getScheduleRemoteBackups(bs.Spec.Audit, bs.Status.RemoteBackups, deletable, retained)
+	if equality.Semantic.DeepEqual(history, bs.Status.BackupScheduleHistory) && equality.Semantic.DeepEqual(remote, bs.Status.RemoteBackups) {
+		return false, nil
+	}
+
+	bs.Status.BackupScheduleHistory = history
+	bs.Status.RemoteBackups = remote
+
+	return true, nil
+}
+
+// UpdateDgraphScheduleHistory sets schedule history status fields, returns true if they are changed
+func UpdateDgraphScheduleHistory(ctx context.Context, rc client.Client, bs *backupsv1alpha1.DgraphBackupSchedule) (bool, error) {
+	bl := &backupsv1alpha1.DgraphBackupList{}
+	if err := rc.List(ctx, bl, client.InNamespace(bs.Namespace), client.MatchingFields{OwnerUIDField: string(bs.UID)}); err != nil {
+		return false, fmt.Errorf("failed to list dgraph backup objects: %w", err)
+	}
+
+	backups := make([]scheduleBackup, 0)
+	var deletable []string
+	for i := range bl.Items {
+		b := &bl.Items[i]
+
+		backups = append(backups, scheduleBackup{
+			obj:            b,
+			phase:          b.Status.Phase,
+			completionTime: b.Status.CompletionTime,
+			size:           b.Status.Size,
+		})
+
+		// dgraph export is always deleted together with backup object, imported ones are never deleted by orphans audit
+		if dir := dgraph.GetExportDir(b); dir != "" && !backupsv1alpha1.IsImported(b) {
+			deletable = append(deletable, dir)
+		}
+	}
+
+	history := getScheduleHistory(backups, getNextScheduleTime(bs.Spec.Suspend, bs.Spec.Schedule, bs.Spec.TimeZone, bs.Spec.Jitter, bs))
+	ObserveLastSuccess(EngineDgraph, bs, history.LastSuccessfulTime)
+
+	remote := getScheduleRemoteBackups(bs.Spec.Audit, bs.Status.RemoteBackups, deletable, nil)
+	if equality.Semantic.DeepEqual(history, bs.Status.BackupScheduleHistory) && equality.Semantic.DeepEqual(remote, bs.Status.RemoteBackups) {
+		return false, nil
+	}
+
+	bs.Status.BackupScheduleHistory = history
+	bs.Status.RemoteBackups = remote
+
+	return true, nil
+}
+
+func getScheduleHistory(backups []scheduleBackup, next *metav1.Time) backupsv1alpha1.BackupScheduleHistory {
+	history := backupsv1alpha1.BackupScheduleHistory{
+		NextScheduleTime: next,
+		Retained:         len(backups),
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backupsv1alpha1.GetCreationTime(backups[i].obj).After(backupsv1alpha1.GetCreationTime(backups[j].obj))
+	})
+
+	for _, b := range backups {
+		if !isContains(finishedPhases, b.phase) {
+			history.Active++
+		}
+
+		// imported backups were not created by schedule
+		if !backupsv1alpha1.IsImported(b.obj) {
+			history.LastScheduleTime = getNewerTime(history.LastScheduleTime, b.obj.GetCreationTimestamp().Time)
+		}
+
+		if b.phase == PhaseCompleted {
+			completed := backupsv1alpha1.GetCreationTime(b.obj)
+			if b.completionTime != nil {
+				completed = b.completionTime.Time
+			}
+
+			history.LastSuccessfulTime = getNewerTime(history.LastSuccessfulTime, completed)
+		}
+
+		if len(history.RecentRuns) < recentRunsLimit {
+			run := backupsv1alpha1.BackupRun{
+				Name:      b.obj.GetName(),
+				Phase:     b.phase,
+				StartTime: metav1.Time{Time: backupsv1alpha1.GetCreationTime(b.obj)},
+				Size:      b.size,
+			}
+
+			if b.completionTime != nil {
+				run.Duration = b.completionTime.Sub(b.obj.GetCreationTimestamp().Time).Round(time.Second).String()
+			}
+
+			history.RecentRuns = append(history.RecentRuns, run)
+		}
+	}
+
+	return history
+}
+
+// getScheduleRemoteBackups returns remote backups, which may be deleted by orphans audit.
+// Remote backups of owned backup objects, which are deleted together with them, are added, retained ones are removed.
+// Nothing is recorded, if audit does not delete orphans.
+func getScheduleRemoteBackups(audit *backupsv1alpha1.BackupAuditSpec, current, deletable, retained []string) []string {
+	if audit == nil || audit.DeleteOrphansAfter == "" {
+		return nil
+	}
+
+	names := make(map[string]struct{})
+	for _, name := range current {
+		names[name] = struct{}{}
+	}
+
+	for _, name := range deletable {
+		names[name] = struct{}{}
+	}
+
+	for _, name := range retained {
+		delete(names, name)
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	out := make([]string, 0, len(names))
+	for name := range names {
+		out = append(out, name)
+	}
+
+	sort.Strings(out)
+
+	return out
+}
+
+// removeString returns copy of given list without given value, nil if it is empty
+func removeString(src []string, value string) []string {
+	var out []string
+	for _, item := range src {
+		if item != value {
+			out = append(out, item)
+		}
+	}
+
+	return out
+}
+
+// getNextScheduleTime returns next backup creation time, nil if schedule is suspended or broken
+func getNextScheduleTime(suspend bool, schedule, timeZone, jitter string, obj metav1.Object) *metav1.Time {
+	if suspend {
+		return nil
+	}
+
+	s, err := ParseBackupSchedule(schedule, timeZone, jitter, obj.GetUID())
+	if err != nil {
+		return nil
+	}
+
+	return &metav1.Time{Time: s.Next(time.Now())}
+}
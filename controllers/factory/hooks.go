@@ -0,0 +1,243 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/hooks"
+)
+
+const (
+	HookPhaseSucceeded = "Succeeded"
+	HookPhaseFailed    = "Failed"
+)
+
+// defaultHookTimeout is hook execution timeout, when it is not set in spec
+const defaultHookTimeout = 30 * time.Second
+
+// hookFinishedPhases is backup phases, when post hooks are executed
+var hookFinishedPhases = []string{PhaseCompleted, PhaseFailed, PhaseCreateFailed, PhaseUploadFailed}
+
+// hooksConfig is rest config used for exec hooks
+var hooksConfig *rest.Config
+
+// SetHooksConfig sets rest config used for running exec hooks in pods
+func SetHooksConfig(cfg *rest.Config) {
+	hooksConfig = cfg
+}
+
+// getHooksConfig returns rest config used for running exec hooks in given namespace.
+// Hooks are run on behalf of credentials service account, when credentials impersonation is enabled,
+// so only pods allowed to this service account by namespace owners may be exec'ed.
+func getHooksConfig(ns string) *rest.Config {
+	if credentialsImpersonation.serviceAccount == "" {
+		return hooksConfig
+	}
+
+	return getImpersonatedConfig(hooksConfig, ns)
+}
+
+// getBackupHooks returns hooks of given stage
+func getBackupHooks(h *backupsv1alpha1.BackupHooks, stage backupsv1alpha1.BackupHookStage) []backupsv1alpha1.BackupHook {
+	if h == nil {
+		return nil
+	}
+
+	if stage == backupsv1alpha1.BackupHookStagePre {
+		return h.Pre
+	}
+
+	return h.Post
+}
+
+// processBackupHooks runs pending hooks of given stage and updates backup object status.
+// Backup is moved to failed phase, when hook with Fail error policy fails.
+func processBackupHooks(ctx context.Context, rc client.Client, l logr.Logger, obj client.Object, h *backupsv1alpha1.BackupHooks, stage backupsv1alpha1.BackupHookStage, phase, message *string, statuses *[]backupsv1alpha1.BackupHookStatus, defaultPod string) error {
+	hs := getBackupHooks(h, stage)
+
+	pending := false
+	for i := range hs {
+		if !isHookExecuted(*statuses, stage, hs[i].Name) {
+			pending = true
+		}
+	}
+
+	if !pending {
+		return nil
+	}
+
+	err := runBackupHooks(ctx, rc, l, obj.GetNamespace(), stage, hs, statuses, defaultPod)
+
+	// original backup error is kept, when backup is already failed
+	if err != nil && !isContains(failedPhases, *phase) {
+		*phase = PhaseFailed
+		*message = err.Error()
+	}
+
+	if err := rc.Status().Update(ctx, obj); err != nil {
+		return fmt.Errorf("failed update status: %w", err)
+	}
+
+	return err
+}
+
+// runBackupHooks executes hooks, which are not recorded in statuses yet, and records their outcomes.
+// Error is returned only for failed hooks with Fail error policy, next hooks are not executed then.
+// Default pod is used by exec hooks without pod and selector.
+func runBackupHooks(ctx context.Context, rc client.Client, l logr.Logger, ns string, stage backupsv1alpha1.BackupHookStage, hs []backupsv1alpha1.BackupHook, statuses *[]backupsv1alpha1.BackupHookStatus, defaultPod string) error {
+	for i := range hs {
+		h := &hs[i]
+
+		if isHookExecuted(*statuses, stage, h.Name) {
+			continue
+		}
+
+		l.V(4).Info("running hook", "name", h.Name, "stage", stage)
+
+		start := time.Now()
+		out, err := runBackupHook(ctx, rc, ns, h, defaultPod)
+
+		status := backupsv1alpha1.BackupHookStatus{
+			Name:      h.Name,
+			Stage:     stage,
+			Phase:     HookPhaseSucceeded,
+			Message:   out,
+			StartTime: metav1.NewTime(start),
+			Duration:  time.Since(start).Round(time.Millisecond).String(),
+		}
+
+		if err != nil {
+			l.Error(err, "hook failed", "name", h.Name, "stage", stage)
+
+			status.Phase = HookPhaseFailed
+			status.Message = err.Error()
+		}
+
+		*statuses = append(*statuses, status)
+
+		if err != nil && h.OnError != backupsv1alpha1.BackupHookOnErrorContinue {
+			return fmt.Errorf("%s hook %q failed: %w", stage, h.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func isHookExecuted(statuses []backupsv1alpha1.BackupHookStatus, stage backupsv1alpha1.BackupHookStage, name string) bool {
+	for _, status := range statuses {
+		if status.Stage == stage && status.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func runBackupHook(ctx context.Context, rc client.Client, ns string, h *backupsv1alpha1.BackupHook, defaultPod string) (string, error) {
+	timeout := defaultHookTimeout
+	if h.Timeout != "" {
+		d, err := time.ParseDuration(h.Timeout)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse timeout: %w", err)
+		}
+
+		timeout = d
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	switch {
+	case h.Exec != nil:
+		if hooksConfig == nil {
+			return "", errors.New("exec hooks are not configured")
+		}
+
+		pod, err := getHookPod(ctx, rc, ns, h.Exec, defaultPod)
+		if err != nil {
+			return "", err
+		}
+
+		out, err := hooks.Exec(ctx, getHooksConfig(ns), ns, pod, h.Exec.Container, h.Exec.Command)
+		if err != nil && credentialsImpersonation.serviceAccount != "" {
+			return out, fmt.Errorf("failed to exec as %s/%s service account: %w", ns, credentialsImpersonation.serviceAccount, err)
+		}
+
+		return out, err
+	case h.HTTP != nil:
+		url, err := getFQDN(h.HTTP.URL, ns)
+		if err != nil {
+			return "", fmt.Errorf("failed to get fqdn: %w", err)
+		}
+
+		return hooks.HTTP(ctx, getValueOrDefault(h.HTTP.Method, http.MethodPost), url, h.HTTP.Headers, h.HTTP.Body)
+	case h.SQL != nil:
+		address, err := getFQDN(h.SQL.Address, ns)
+		if err != nil {
+			return "", fmt.Errorf("failed to get fqdn: %w", err)
+		}
+
+		var username, password string
+		if auth := h.SQL.Auth; auth != nil {
+			if username, err = getSecretValue(ctx, rc, ns, auth.SecretName, getValueOrDefault(auth.UsernameKey, "username")); err != nil {
+				return "", fmt.Errorf("failed to get username: %w", err)
+			}
+
+			if password, err = getSecretValue(ctx, rc, ns, auth.SecretName, getValueOrDefault(auth.PasswordKey, "password")); err != nil {
+				return "", fmt.Errorf("failed to get password: %w", err)
+			}
+		}
+
+		return hooks.SQL(ctx, address, username, password, h.SQL.Query)
+	default:
+		return "", errors.New("one of exec, http or sql must be set")
+	}
+}
+
+// getHookPod returns name of pod, where exec hook is run
+func getHookPod(ctx context.Context, rc client.Client, ns string, e *backupsv1alpha1.BackupHookExec, defaultPod string) (string, error) {
+	if e.Pod != "" {
+		return e.Pod, nil
+	}
+
+	if e.PodSelector == nil {
+		if defaultPod == "" {
+			return "", errors.New("pod or pod selector must be set")
+		}
+
+		return defaultPod, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(e.PodSelector)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse pod selector: %w", err)
+	}
+
+	pl := &v1.PodList{}
+	if err := rc.List(ctx, pl, client.InNamespace(ns), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	sort.Slice(pl.Items, func(i, j int) bool {
+		return pl.Items[i].Name < pl.Items[j].Name
+	})
+
+	for i := range pl.Items {
+		if isPodReady(&pl.Items[i]) {
+			return pl.Items[i].Name, nil
+		}
+	}
+
+	return "", errors.New("ready pod not found by selector")
+}
@@ -0,0 +1,39 @@
+package factory
+
+import (
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestGetHooksConfig(t *testing.T) {
+	defer SetHooksConfig(hooksConfig)
+	defer SetCredentialsImpersonation(credentialsImpersonation.config, credentialsImpersonation.serviceAccount)
+
+	cfg := &rest.Config{Host: "https://kubernetes.default.svc"}
+	SetHooksConfig(cfg)
+
+	tests := []struct {
+		name           string
+		serviceAccount string
+		user           string
+	}{
+		{name: "operator", serviceAccount: "", user: ""},
+		{name: "impersonated", serviceAccount: "backups-credentials", user: "system:serviceaccount:tenant-a:backups-credentials"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetCredentialsImpersonation(cfg, tt.serviceAccount)
+
+			c := getHooksConfig("tenant-a")
+			if c.Host != cfg.Host || c.Impersonate.UserName != tt.user {
+				t.Errorf("unexpected hooks config host %q user %q", c.Host, c.Impersonate.UserName)
+			}
+		})
+	}
+
+	if cfg.Impersonate.UserName != "" {
+		t.Errorf("operator config is changed: %+v", cfg.Impersonate)
+	}
+}
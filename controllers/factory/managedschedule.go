@@ -0,0 +1,205 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+)
+
+// syncClickHouseBackupSchedule creates or updates schedule controlled by owner, which spec is rendered from template
+func syncClickHouseBackupSchedule(ctx context.Context, rc client.Client, l logr.Logger, owner metav1.Object, om metav1.ObjectMeta, template *backupsv1alpha1.ClickHouseBackupScheduleSpec, data interface{}) error {
+	spec := backupsv1alpha1.ClickHouseBackupScheduleSpec{}
+	if err := renderTemplate(template, &spec, data); err != nil {
+		return fmt.Errorf("failed to render clickhouse backup schedule: %w", err)
+	}
+
+	bs := &backupsv1alpha1.ClickHouseBackupSchedule{}
+	err := rc.Get(ctx, types.NamespacedName{Name: om.Name, Namespace: om.Namespace}, bs)
+	if errors.IsNotFound(err) {
+		l.V(3).Info("creating clickhouse backup schedule object", "name", om.Name, "namespace", om.Namespace)
+
+		bs = &backupsv1alpha1.ClickHouseBackupSchedule{ObjectMeta: om, Spec: spec}
+		if err := rc.Create(ctx, bs); err != nil {
+			return fmt.Errorf("failed to create clickhouse backup schedule object: %w", err)
+		}
+
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get clickhouse backup schedule object: %w", err)
+	}
+
+	if !metav1.IsControlledBy(bs, owner) {
+		return fmt.Errorf("clickhouse backup schedule %s/%s is controlled by another object", bs.Namespace, bs.Name)
+	}
+
+	_, lost := bs.Annotations[backupsv1alpha1.TargetLostAnnotation]
+	if equality.Semantic.DeepEqual(bs.Spec, spec) && !lost {
+		return nil
+	}
+
+	l.V(3).Info("updating clickhouse backup schedule object", "name", om.Name, "namespace", om.Namespace)
+
+	// target is discovered again, so suspended schedule is restored
+	delete(bs.Annotations, backupsv1alpha1.TargetLostAnnotation)
+	bs.Spec = spec
+	if err := rc.Update(ctx, bs); err != nil {
+		return fmt.Errorf("failed update clickhouse backup schedule object: %w", err)
+	}
+
+	return nil
+}
+
+// syncDgraphBackupSchedule creates or updates schedule controlled by owner, which spec is rendered from template
+func syncDgraphBackupSchedule(ctx context.Context, rc client.Client, l logr.Logger, owner metav1.Object, om metav1.ObjectMeta, template *backupsv1alpha1.DgraphBackupScheduleSpec, data interface{}) error {
+	spec := backupsv1alpha1.DgraphBackupScheduleSpec{}
+	if err := renderTemplate(template, &spec, data); err != nil {
+		return fmt.Errorf("failed to render dgraph backup schedule: %w", err)
+	}
+
+	bs := &backupsv1alpha1.DgraphBackupSchedule{}
+	err := rc.Get(ctx, types.NamespacedName{Name: om.Name, Namespace: om.Namespace}, bs)
+	if errors.IsNotFound(err) {
+		l.V(3).Info("creating dgraph backup schedule object", "name", om.Name, "namespace", om.Namespace)
+
+		bs = &backupsv1alpha1.DgraphBackupSchedule{ObjectMeta: om, Spec: spec}
+		if err := rc.Create(ctx, bs); err != nil {
+			return fmt.Errorf("failed to create dgraph backup schedule object: %w", err)
+		}
+
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get dgraph backup schedule object: %w", err)
+	}
+
+	if !metav1.IsControlledBy(bs, owner) {
+		return fmt.Errorf("dgraph backup schedule %s/%s is controlled by another object", bs.Namespace, bs.Name)
+	}
+
+	_, lost := bs.Annotations[backupsv1alpha1.TargetLostAnnotation]
+	if equality.Semantic.DeepEqual(bs.Spec, spec) && !lost {
+		return nil
+	}
+
+	l.V(3).Info("updating dgraph backup schedule object", "name", om.Name, "namespace", om.Namespace)
+
+	// target is discovered again, so suspended schedule is restored
+	delete(bs.Annotations, backupsv1alpha1.TargetLostAnnotation)
+	bs.Spec = spec
+	if err := rc.Update(ctx, bs); err != nil {
+		return fmt.Errorf("failed update dgraph backup schedule object: %w", err)
+	}
+
+	return nil
+}
+
+// deleteBackupSchedules deletes schedules controlled by owner and matching labels, unless keep returns true for them.
+// Backups of deleted schedules are kept.
+// With positive grace period schedule is suspended and marked first, it is deleted, when grace period is over.
+// Time until next marked schedule deletion is returned.
+func deleteBackupSchedules(ctx context.Context, rc client.Client, l logr.Logger, owner metav1.Object, labels client.MatchingLabels, keep func(engine string, bs metav1.Object) bool, grace time.Duration) (time.Duration, error) {
+	chl := &backupsv1alpha1.ClickHouseBackupScheduleList{}
+	if err := rc.List(ctx, chl, labels); err != nil {
+		return 0, fmt.Errorf("failed to list clickhouse backup schedule objects: %w", err)
+	}
+
+	objs := make([]client.Object, 0)
+	for i := range chl.Items {
+		bs := &chl.Items[i]
+
+		if !keep(EngineClickHouse, bs) {
+			objs = append(objs, bs)
+		}
+	}
+
+	dgl := &backupsv1alpha1.DgraphBackupScheduleList{}
+	if err := rc.List(ctx, dgl, labels); err != nil {
+		return 0, fmt.Errorf("failed to list dgraph backup schedule objects: %w", err)
+	}
+
+	for i := range dgl.Items {
+		bs := &dgl.Items[i]
+
+		if !keep(EngineDgraph, bs) {
+			objs = append(objs, bs)
+		}
+	}
+
+	var next time.Duration
+	for _, obj := range objs {
+		if !metav1.IsControlledBy(obj, owner) {
+			continue
+		}
+
+		if grace > 0 {
+			left, err := markLostBackupSchedule(ctx, rc, l, obj, grace)
+			if err != nil {
+				return 0, err
+			}
+
+			if left > 0 {
+				if next == 0 || left < next {
+					next = left
+				}
+
+				continue
+			}
+		}
+
+		l.V(3).Info("deleting backup schedule object", "name", obj.GetName(), "namespace", obj.GetNamespace())
+
+		// backups are orphaned, so their data is not deleted together with schedule
+		if err := rc.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationOrphan)); client.IgnoreNotFound(err) != nil {
+			return 0, fmt.Errorf("failed to delete backup schedule object: %w", err)
+		}
+	}
+
+	return next, nil
+}
+
+// markLostBackupSchedule suspends schedule and records time, when its target was lost.
+// Time left until grace period is over is returned.
+func markLostBackupSchedule(ctx context.Context, rc client.Client, l logr.Logger, obj client.Object, grace time.Duration) (time.Duration, error) {
+	if value, ok := obj.GetAnnotations()[backupsv1alpha1.TargetLostAnnotation]; ok {
+		lost, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse %s annotation: %w", backupsv1alpha1.TargetLostAnnotation, err)
+		}
+
+		return time.Until(lost.Add(grace)), nil
+	}
+
+	l.V(3).Info("suspending backup schedule object of lost target", "name", obj.GetName(), "namespace", obj.GetNamespace())
+
+	switch bs := obj.(type) {
+	case *backupsv1alpha1.ClickHouseBackupSchedule:
+		bs.Spec.Suspend = true
+		bs.Spec.SuspendRetention = true
+	case *backupsv1alpha1.DgraphBackupSchedule:
+		bs.Spec.Suspend = true
+		bs.Spec.SuspendRetention = true
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[backupsv1alpha1.TargetLostAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	obj.SetAnnotations(annotations)
+
+	if err := rc.Update(ctx, obj); err != nil {
+		return 0, fmt.Errorf("failed update backup schedule object: %w", err)
+	}
+
+	return grace, nil
+}
@@ -0,0 +1,218 @@
+package factory
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sputnik-systems/backups-operator/internal/metrics"
+	"github.com/sputnik-systems/backups-operator/internal/monitoring"
+)
+
+const (
+	EngineClickHouse = "clickhouse"
+	EngineDgraph     = "dgraph"
+
+	OperationCreate   = "create"
+	OperationUpload   = "upload"
+	OperationDownload = "download"
+	OperationRestore  = "restore"
+)
+
+var (
+	phases = []string{
+		PhaseStarted,
+		PhaseFailed,
+		PhaseCompleted,
+		PhaseCreating,
+		PhaseCreated,
+		PhaseCreateFailed,
+		PhaseUploading,
+		PhaseUploadFailed,
+		PhaseMissing,
+	}
+
+	operations = []string{OperationCreate, OperationUpload, OperationDownload, OperationRestore}
+
+	// scheduledTaskActions is list of schedule tasks failures counter action label values
+	scheduledTaskActions = []string{"create", "remove", "audit", "freshness"}
+)
+
+// ObservePhaseTransition counts backup object phase change.
+// Completed phase also updates last success timestamp of backup schedule.
+func ObservePhaseTransition(engine string, obj metav1.Object, from, to string) {
+	labels := getScheduleMetricsLabels(engine, obj)
+
+	metrics.PhaseTransitionsBySchedule.With(withLabel(labels, "phase", to)).Inc()
+
+	backupLabels := withLabel(labels, "name", obj.GetName())
+	if from != "" {
+		metrics.BackupPhaseTimestampBySchedule.Delete(withLabel(backupLabels, "phase", from))
+	}
+	metrics.BackupPhaseTimestampBySchedule.With(withLabel(backupLabels, "phase", to)).SetToCurrentTime()
+
+	if to == PhaseCompleted {
+		metrics.LastSuccessTimestampBySchedule.With(labels).SetToCurrentTime()
+	}
+}
+
+// ObserveLastSuccess restores last success timestamp of backup schedule from its newest completed backup,
+// so metric survives manager restarts
+func ObserveLastSuccess(engine string, obj metav1.Object, last *metav1.Time) {
+	if last == nil {
+		return
+	}
+
+	metrics.LastSuccessTimestampBySchedule.With(
+		prometheus.Labels{
+			"engine":    engine,
+			"schedule":  obj.GetName(),
+			"namespace": obj.GetNamespace(),
+		},
+	).Set(float64(last.Unix()))
+}
+
+// ObserveScheduleInterval updates backup schedule interval metric, which is used by alerting rules
+func ObserveScheduleInterval(engine, name, namespace, schedule string) error {
+	interval, err := monitoring.ScheduleInterval(schedule)
+	if err != nil {
+		return err
+	}
+
+	metrics.ScheduleIntervalBySchedule.With(
+		prometheus.Labels{
+			"engine":    engine,
+			"schedule":  name,
+			"namespace": namespace,
+		},
+	).Set(interval.Seconds())
+
+	return nil
+}
+
+// ObserveRetention updates retention metrics of backup schedule
+func ObserveRetention(engine, schedule, namespace string, retained, deleted int) {
+	labels := prometheus.Labels{
+		"engine":    engine,
+		"schedule":  schedule,
+		"namespace": namespace,
+	}
+
+	metrics.RetainedBackupsBySchedule.With(labels).Set(float64(retained))
+	metrics.RetentionDeletionsBySchedule.With(labels).Add(float64(deleted))
+}
+
+// DeleteBackupMetrics removes metrics of given backup object
+func DeleteBackupMetrics(engine, controller string, obj metav1.Object, phase string) {
+	for _, status := range []string{"success", "failed"} {
+		metrics.BackupsByController.Delete(
+			prometheus.Labels{
+				"name":       obj.GetName(),
+				"namespace":  obj.GetNamespace(),
+				"controller": controller,
+				"status":     status,
+			},
+		)
+	}
+
+	labels := withLabel(getScheduleMetricsLabels(engine, obj), "name", obj.GetName())
+	metrics.BackupPhaseTimestampBySchedule.Delete(withLabel(labels, "phase", phase))
+}
+
+// DeleteScheduleMetrics removes metrics of given backup schedule
+func DeleteScheduleMetrics(engine, name, namespace, controller string) {
+	labels := prometheus.Labels{
+		"engine":    engine,
+		"schedule":  name,
+		"namespace": namespace,
+	}
+
+	metrics.LastSuccessTimestampBySchedule.Delete(labels)
+	metrics.BackupSizeBySchedule.Delete(labels)
+	metrics.RetainedBackupsBySchedule.Delete(labels)
+	metrics.RetentionDeletionsBySchedule.Delete(labels)
+	metrics.ScheduleIntervalBySchedule.Delete(labels)
+	metrics.StaleBySchedule.Delete(labels)
+
+	for _, phase := range phases {
+		metrics.PhaseTransitionsBySchedule.Delete(
+			prometheus.Labels{
+				"engine":    engine,
+				"schedule":  name,
+				"namespace": namespace,
+				"phase":     phase,
+			},
+		)
+	}
+
+	for _, reason := range []string{SkipReasonSuspended, SkipReasonBlackout, SkipReasonNameConflict} {
+		metrics.SkippedTicksBySchedule.Delete(
+			prometheus.Labels{
+				"engine":    engine,
+				"schedule":  name,
+				"namespace": namespace,
+				"reason":    reason,
+			},
+		)
+	}
+
+	for _, operation := range operations {
+		metrics.OperationDurationBySchedule.Delete(
+			prometheus.Labels{
+				"engine":    engine,
+				"schedule":  name,
+				"namespace": namespace,
+				"operation": operation,
+			},
+		)
+	}
+
+	for _, action := range scheduledTaskActions {
+		metrics.ScheduledTaskFailuresByControllerTotal.Delete(
+			prometheus.Labels{
+				"name":       name,
+				"namespace":  namespace,
+				"controller": controller,
+				"action":     action,
+			},
+		)
+	}
+
+	DeleteAuditMetrics(name, namespace, controller)
+}
+
+func observeOperationDuration(engine string, obj metav1.Object, operation string, d time.Duration) {
+	labels := withLabel(getScheduleMetricsLabels(engine, obj), "operation", operation)
+
+	metrics.OperationDurationBySchedule.With(labels).Observe(d.Seconds())
+}
+
+func observeBackupSize(engine string, obj metav1.Object, size int64) {
+	metrics.BackupSizeBySchedule.With(getScheduleMetricsLabels(engine, obj)).Set(float64(size))
+}
+
+// getScheduleMetricsLabels returns labels of backup object schedule.
+// Schedule label is empty for backups created manually.
+func getScheduleMetricsLabels(engine string, obj metav1.Object) prometheus.Labels {
+	var schedule string
+	if owner := metav1.GetControllerOf(obj); owner != nil {
+		schedule = owner.Name
+	}
+
+	return prometheus.Labels{
+		"engine":    engine,
+		"schedule":  schedule,
+		"namespace": obj.GetNamespace(),
+	}
+}
+
+// withLabel returns copy of labels with additional label
+func withLabel(labels prometheus.Labels, name, value string) prometheus.Labels {
+	out := prometheus.Labels{name: value}
+	for key, value := range labels {
+		out[key] = value
+	}
+
+	return out
+}
@@ -0,0 +1,53 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sputnik-systems/backups-operator/internal/monitoring"
+)
+
+// ReconcilePrometheusRule creates or updates PrometheusRule with alerts of given backup schedule.
+// Alert thresholds are derived from schedule interval, rule is removed with schedule by owner reference.
+func ReconcilePrometheusRule(ctx context.Context, rc client.Client, engine string, obj metav1.Object, owner []metav1.OwnerReference, schedule string) error {
+	interval, err := monitoring.ScheduleInterval(schedule)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s-backups", obj.GetName(), engine)
+
+	rule, err := monitoring.PrometheusRule(name, obj.GetNamespace(), monitoring.ScheduleRules(engine, obj.GetName(), obj.GetNamespace(), interval))
+	if err != nil {
+		return err
+	}
+
+	rule.SetOwnerReferences(owner)
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(monitoring.PrometheusRuleGVK)
+	if err := rc.Get(ctx, types.NamespacedName{Name: name, Namespace: obj.GetNamespace()}, current); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get prometheus rule: %w", err)
+		}
+
+		if err := rc.Create(ctx, rule); err != nil {
+			return fmt.Errorf("failed to create prometheus rule: %w", err)
+		}
+
+		return nil
+	}
+
+	rule.SetResourceVersion(current.GetResourceVersion())
+	if err := rc.Update(ctx, rule); err != nil {
+		return fmt.Errorf("failed to update prometheus rule: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/notify"
+)
+
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=backupnotifications,verbs=get;list;watch
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=backupnotifications/status,verbs=get;update;patch
+
+// failedPhases is list of phases, which are notified as failed event
+var failedPhases = []string{PhaseFailed, PhaseCreateFailed, PhaseUploadFailed}
+
+// notifier sends events to sinks of matching BackupNotification objects in event namespace
+type notifier struct {
+	rc client.Client
+	l  logr.Logger
+}
+
+// NewNotifier returns notifier, which is configured by BackupNotification objects
+func NewNotifier(rc client.Client, l logr.Logger) notify.Notifier {
+	return &notifier{rc: rc, l: l}
+}
+
+func (n *notifier) Notify(ctx context.Context, e notify.Event) error {
+	nl := &backupsv1alpha1.BackupNotificationList{}
+	if err := n.rc.List(ctx, nl, client.InNamespace(e.Namespace)); err != nil {
+		return fmt.Errorf("failed to list backup notification objects: %w", err)
+	}
+
+	var errs []error
+	for i := range nl.Items {
+		bn := &nl.Items[i]
+
+		if !isNotificationMatch(bn, e) {
+			continue
+		}
+
+		n.l.V(4).Info("sending notification", "notification", bn.Name, "reason", e.Reason)
+
+		err := sendNotification(ctx, n.rc, bn, e)
+
+		bn.Status.Error = ""
+		if err != nil {
+			bn.Status.Error = err.Error()
+			errs = append(errs, fmt.Errorf("%s: %w", bn.Name, err))
+		} else {
+			bn.Status.Sent++
+			bn.Status.LastSentTime = metav1.Now()
+		}
+
+		if err := n.rc.Status().Update(ctx, bn); err != nil {
+			n.l.Error(err, "failed update backup notification object")
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to send notifications: %v", errs)
+	}
+
+	return nil
+}
+
+// NotifyPhaseTransition sends completed or failed event of backup object
+func NotifyPhaseTransition(ctx context.Context, l logr.Logger, n notify.Notifier, engine string, obj metav1.Object, phase, errMessage string, size int64) {
+	if n == nil {
+		return
+	}
+
+	var reason string
+	switch {
+	case phase == PhaseCompleted:
+		reason = notify.EventCompleted
+	case isContains(failedPhases, phase):
+		reason = notify.EventFailed
+	default:
+		return
+	}
+
+	e := newBackupEvent(reason, engine, obj)
+	e.Phase = phase
+	e.Error = errMessage
+	e.Size = size
+	e.Duration = time.Since(obj.GetCreationTimestamp().Time).Seconds()
+
+	if err := n.Notify(ctx, e); err != nil {
+		l.Error(err, "failed to send notification")
+	}
+}
+
+// NotifyRetentionDeleted sends event about backup object deleted by retention
+func NotifyRetentionDeleted(ctx context.Context, l logr.Logger, n notify.Notifier, engine string, obj metav1.Object, phase string) {
+	if n == nil {
+		return
+	}
+
+	e := newBackupEvent(notify.EventRetentionDeleted, engine, obj)
+	e.Phase = phase
+
+	if err := n.Notify(ctx, e); err != nil {
+		l.Error(err, "failed to send notification")
+	}
+}
+
+func newBackupEvent(reason, engine string, obj metav1.Object) notify.Event {
+	var schedule string
+	if owner := metav1.GetControllerOf(obj); owner != nil {
+		schedule = owner.Name
+	}
+
+	return notify.Event{
+		Reason:    reason,
+		Engine:    engine,
+		Schedule:  schedule,
+		Namespace: obj.GetNamespace(),
+		Backup:    obj.GetName(),
+		Time:      time.Now(),
+	}
+}
+
+func isNotificationMatch(bn *backupsv1alpha1.BackupNotification, e notify.Event) bool {
+	if len(bn.Spec.Events) > 0 && !isContains(bn.Spec.Events, e.Reason) {
+		return false
+	}
+
+	if len(bn.Spec.Engines) > 0 && !isContains(bn.Spec.Engines, e.Engine) {
+		return false
+	}
+
+	if len(bn.Spec.Schedules) > 0 && !isContains(bn.Spec.Schedules, e.Schedule) {
+		return false
+	}
+
+	return true
+}
+
+func sendNotification(ctx context.Context, rc client.Client, bn *backupsv1alpha1.BackupNotification, e notify.Event) error {
+	sinks, err := getNotificationSinks(ctx, rc, bn)
+	if err != nil {
+		return err
+	}
+
+	if len(sinks) == 0 {
+		return errors.New("notification sinks are not configured")
+	}
+
+	for _, sink := range sinks {
+		if err := sink.Send(ctx, e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getNotificationSinks(ctx context.Context, rc client.Client, bn *backupsv1alpha1.BackupNotification) ([]notify.Sink, error) {
+	sinks := make([]notify.Sink, 0)
+
+	if spec := bn.Spec.Webhook; spec != nil {
+		url := spec.URL
+		if url == "" {
+			var err error
+			url, err = getSecretValue(ctx, rc, bn.Namespace, spec.SecretName, getValueOrDefault(spec.URLKey, "url"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get webhook url: %w", err)
+			}
+		}
+
+		sinks = append(sinks, &notify.Webhook{URL: url, Headers: spec.Headers})
+	}
+
+	if spec := bn.Spec.Slack; spec != nil {
+		url, err := getSecretValue(ctx, rc, bn.Namespace, spec.SecretName, getValueOrDefault(spec.URLKey, "url"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get slack webhook url: %w", err)
+		}
+
+		sinks = append(sinks, &notify.Slack{URL: url, Channel: spec.Channel, Username: spec.Username})
+	}
+
+	if spec := bn.Spec.SMTP; spec != nil {
+		sink := &notify.SMTP{
+			Host: spec.Host,
+			Port: spec.Port,
+			From: spec.From,
+			To:   spec.To,
+		}
+
+		if sink.Port == 0 {
+			sink.Port = 587
+		}
+
+		if spec.SecretName != "" {
+			var err error
+			sink.Username, err = getSecretValue(ctx, rc, bn.Namespace, spec.SecretName, getValueOrDefault(spec.UsernameKey, "username"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get smtp username: %w", err)
+			}
+
+			sink.Password, err = getSecretValue(ctx, rc, bn.Namespace, spec.SecretName, getValueOrDefault(spec.PasswordKey, "password"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get smtp password: %w", err)
+			}
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	return sinks, nil
+}
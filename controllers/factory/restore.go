@@ -0,0 +1,120 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+)
+
+// ErrBackupNotCompleted is returned, when restored backup is not finished yet, so restore should be retried later
+var ErrBackupNotCompleted = errors.New("backup is not completed yet")
+
+// restoreFinishedPhases is restore phases, when restore object is not processed anymore
+var restoreFinishedPhases = []string{PhaseCompleted, PhaseFailed}
+
+// checkRestoreSource checks if backup object may be restored by restore object in given namespace
+func checkRestoreSource(b metav1.Object, phase, ns string) error {
+	if !backupsv1alpha1.IsRestoreAllowed(b, ns) {
+		return fmt.Errorf("backup %s/%s does not allow restore in namespace %q by %s annotation",
+			b.GetNamespace(), b.GetName(), ns, backupsv1alpha1.RestoreNamespacesAnnotation)
+	}
+
+	switch {
+	case phase == PhaseCompleted:
+		return nil
+	case phase == PhaseMissing || isContains(failedPhases, phase):
+		return fmt.Errorf("backup %s/%s is in %s phase", b.GetNamespace(), b.GetName(), phase)
+	default:
+		return ErrBackupNotCompleted
+	}
+}
+
+// externalRestoreTargets allows restore into api addresses, which namespace is unknown
+var externalRestoreTargets bool
+
+// SetExternalRestoreTargets allows restore objects to use ip addresses and hosts outside of cluster as restore target
+func SetExternalRestoreTargets(allowed bool) {
+	externalRestoreTargets = allowed
+}
+
+// checkRestoreTarget checks if restore object in given namespace may restore backup by api address.
+// Target in other namespace must allow restore namespace by annotation.
+// Pods found by pod selector are always in restore namespace, so address host is not checked then.
+func checkRestoreTarget(ctx context.Context, rc client.Client, ns, address string, podSelector *metav1.LabelSelector) error {
+	if podSelector != nil {
+		return nil
+	}
+
+	hostname, err := getHostname(address)
+	if err != nil {
+		return err
+	}
+
+	target, ok := getHostnameNamespace(hostname)
+	if !ok {
+		if externalRestoreTargets {
+			return nil
+		}
+
+		return fmt.Errorf("restore target %q is not cluster service, external restore targets are disabled by operator", hostname)
+	}
+
+	if target == ns {
+		return nil
+	}
+
+	tns := &v1.Namespace{}
+	if err := rc.Get(ctx, types.NamespacedName{Name: target}, tns); err != nil {
+		return fmt.Errorf("failed to get restore target namespace: %w", err)
+	}
+
+	if !backupsv1alpha1.IsNamespaceListed(tns, backupsv1alpha1.RestoreTargetNamespacesAnnotation, ns) {
+		return fmt.Errorf("namespace %s does not allow restore from namespace %q by %s annotation",
+			target, ns, backupsv1alpha1.RestoreTargetNamespacesAnnotation)
+	}
+
+	return nil
+}
+
+// getHostnameNamespace returns namespace of cluster service or pod dns name,
+// e.g. <service>.<namespace>, <service>.<namespace>.svc[.<cluster domain>] or <pod>.<subdomain>.<namespace>.svc
+func getHostnameNamespace(hostname string) (string, bool) {
+	if net.ParseIP(hostname) != nil {
+		return "", false
+	}
+
+	labels := strings.Split(hostname, ".")
+	for i := 2; i < len(labels); i++ {
+		if labels[i] == "svc" {
+			return labels[i-1], true
+		}
+	}
+
+	if len(labels) == 2 {
+		return labels[1], true
+	}
+
+	return "", false
+}
+
+// getMappingParam returns clickhouse-backup mapping param value, e.g. src:dst,src2:dst2
+func getMappingParam(m map[string]string) string {
+	items := make([]string, 0, len(m))
+	for src, dst := range m {
+		items = append(items, src+":"+dst)
+	}
+
+	sort.Strings(items)
+
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,309 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+)
+
+var (
+	// ErrNoCompletedBackup is returned, when source schedule has no completed backup allowed for restore
+	ErrNoCompletedBackup = errors.New("there is no completed backup to restore")
+
+	// ErrRestoreInProgress is returned, when previous restore object of schedule is not finished yet
+	ErrRestoreInProgress = errors.New("previous restore is not finished yet")
+)
+
+// scheduleRestore is engine independent restore object info used for restore schedule history
+type scheduleRestore struct {
+	obj            client.Object
+	backup         string
+	phase          string
+	completionTime *metav1.Time
+}
+
+// CheckRestoreScheduleSpec checks if restore options are set for restore schedule source kind
+func CheckRestoreScheduleSpec(rs *backupsv1alpha1.RestoreSchedule) error {
+	switch rs.Spec.Source.Kind {
+	case backupsv1alpha1.RestoreScheduleSourceClickHouse:
+		if rs.Spec.ClickHouse == nil {
+			return fmt.Errorf("clickhouse restore options are required for %s source", rs.Spec.Source.Kind)
+		}
+	case backupsv1alpha1.RestoreScheduleSourceDgraph:
+		if rs.Spec.Dgraph == nil {
+			return fmt.Errorf("dgraph restore options are required for %s source", rs.Spec.Source.Kind)
+		}
+	default:
+		return fmt.Errorf("unsupported restore schedule source kind %q", rs.Spec.Source.Kind)
+	}
+
+	if rs.Spec.Source.Selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(rs.Spec.Source.Selector); err != nil {
+			return fmt.Errorf("failed to parse source selector: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// NewScheduleRestore returns restore object owned by given restore schedule for schedule tick at given time.
+// Newest completed source schedule backup is restored.
+func NewScheduleRestore(ctx context.Context, rc client.Client, rs *backupsv1alpha1.RestoreSchedule, tick time.Time) (client.Object, error) {
+	restores, err := getScheduleRestores(ctx, rc, rs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range restores {
+		if !isContains(restoreFinishedPhases, r.phase) {
+			return nil, ErrRestoreInProgress
+		}
+	}
+
+	b, err := getNewestRestorableBackup(ctx, rc, rs)
+	if err != nil {
+		return nil, err
+	}
+
+	om, err := getScheduleBackupMeta(rs, nil, rs.Spec.TimeZone, tick)
+	if err != nil {
+		return nil, err
+	}
+
+	om.OwnerReferences = rs.AsOwner()
+
+	ref := backupsv1alpha1.BackupReference{Name: b.GetName()}
+	if b.GetNamespace() != rs.Namespace {
+		ref.Namespace = b.GetNamespace()
+	}
+
+	switch rs.Spec.Source.Kind {
+	case backupsv1alpha1.RestoreScheduleSourceClickHouse:
+		r := &backupsv1alpha1.ClickHouseRestore{
+			ObjectMeta: om,
+			Spec:       *rs.Spec.ClickHouse.DeepCopy(),
+		}
+		r.Spec.Backup = ref
+
+		return r, nil
+	default:
+		r := &backupsv1alpha1.DgraphRestore{
+			ObjectMeta: om,
+			Spec:       *rs.Spec.Dgraph.DeepCopy(),
+		}
+		r.Spec.Backup = ref
+
+		return r, nil
+	}
+}
+
+// SetRestoreScheduleError records reason of skipped refresh in restore schedule status, nil error clears it
+func SetRestoreScheduleError(ctx context.Context, rc client.Client, key types.NamespacedName, reason error) error {
+	rs := &backupsv1alpha1.RestoreSchedule{}
+	if err := rc.Get(ctx, key, rs); err != nil {
+		return fmt.Errorf("failed to get restore schedule object: %w", err)
+	}
+
+	msg := ""
+	if reason != nil {
+		msg = reason.Error()
+	}
+
+	if rs.Status.Error == msg {
+		return nil
+	}
+
+	rs.Status.Error = msg
+	if err := rc.Status().Update(ctx, rs); err != nil {
+		return fmt.Errorf("failed update restore schedule object: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateRestoreScheduleHistory deletes finished restore objects over history limit
+// and sets restore schedule history status fields, returns true if they are changed
+func UpdateRestoreScheduleHistory(ctx context.Context, rc client.Client, rs *backupsv1alpha1.RestoreSchedule) (bool, error) {
+	restores, err := getScheduleRestores(ctx, rc, rs)
+	if err != nil {
+		return false, err
+	}
+
+	sort.Slice(restores, func(i, j int) bool {
+		return restores[i].obj.GetCreationTimestamp().After(restores[j].obj.GetCreationTimestamp().Time)
+	})
+
+	limit := rs.Spec.HistoryLimit
+	if limit <= 0 {
+		limit = 1
+	}
+
+	history := make([]backupsv1alpha1.RestoreRun, 0, len(restores))
+	var lastScheduleTime, lastSuccessfulTime *metav1.Time
+	var lastRestoredBackup string
+	for i, r := range restores {
+		// unfinished restore objects are kept, so running restore is not interrupted
+		if i >= limit && isContains(restoreFinishedPhases, r.phase) {
+			if err := rc.Delete(ctx, r.obj); client.IgnoreNotFound(err) != nil {
+				return false, fmt.Errorf("failed to delete restore object %s: %w", r.obj.GetName(), err)
+			}
+
+			continue
+		}
+
+		if i == 0 {
+			lastScheduleTime = &metav1.Time{Time: r.obj.GetCreationTimestamp().Time}
+			lastRestoredBackup = r.backup
+		}
+
+		if r.phase == PhaseCompleted && r.completionTime != nil {
+			lastSuccessfulTime = getNewerTime(lastSuccessfulTime, r.completionTime.Time)
+		}
+
+		history = append(history, backupsv1alpha1.RestoreRun{
+			Name:           r.obj.GetName(),
+			Backup:         r.backup,
+			Phase:          r.phase,
+			StartTime:      r.obj.GetCreationTimestamp(),
+			CompletionTime: r.completionTime,
+		})
+	}
+
+	if len(history) == 0 {
+		history = nil
+	}
+
+	status := rs.Status.DeepCopy()
+	status.History = history
+	status.LastScheduleTime = lastScheduleTime
+	status.LastSuccessfulTime = lastSuccessfulTime
+	status.LastRestoredBackup = lastRestoredBackup
+	if equality.Semantic.DeepEqual(status, &rs.Status) {
+		return false, nil
+	}
+
+	rs.Status = *status
+
+	return true, nil
+}
+
+// getScheduleRestores returns restore objects owned by given restore schedule
+func getScheduleRestores(ctx context.Context, rc client.Client, rs *backupsv1alpha1.RestoreSchedule) ([]scheduleRestore, error) {
+	opts := []client.ListOption{client.InNamespace(rs.Namespace), client.MatchingFields{OwnerUIDField: string(rs.UID)}}
+
+	restores := make([]scheduleRestore, 0)
+	switch rs.Spec.Source.Kind {
+	case backupsv1alpha1.RestoreScheduleSourceClickHouse:
+		rl := &backupsv1alpha1.ClickHouseRestoreList{}
+		if err := rc.List(ctx, rl, opts...); err != nil {
+			return nil, fmt.Errorf("failed to list clickhouse restore objects: %w", err)
+		}
+
+		for i := range rl.Items {
+			r := &rl.Items[i]
+
+			restores = append(restores, scheduleRestore{
+				obj:            r,
+				backup:         r.Spec.Backup.Name,
+				phase:          r.Status.Phase,
+				completionTime: r.Status.CompletionTime,
+			})
+		}
+	case backupsv1alpha1.RestoreScheduleSourceDgraph:
+		rl := &backupsv1alpha1.DgraphRestoreList{}
+		if err := rc.List(ctx, rl, opts...); err != nil {
+			return nil, fmt.Errorf("failed to list dgraph restore objects: %w", err)
+		}
+
+		for i := range rl.Items {
+			r := &rl.Items[i]
+
+			restores = append(restores, scheduleRestore{
+				obj:            r,
+				backup:         r.Spec.Backup.Name,
+				phase:          r.Status.Phase,
+				completionTime: r.Status.CompletionTime,
+			})
+		}
+	}
+
+	return restores, nil
+}
+
+// getNewestRestorableBackup returns newest completed backup of restore schedule source,
+// which may be restored in restore schedule namespace
+func getNewestRestorableBackup(ctx context.Context, rc client.Client, rs *backupsv1alpha1.RestoreSchedule) (metav1.Object, error) {
+	selector := labels.NewSelector()
+	if rs.Spec.Source.Selector != nil {
+		s, err := metav1.LabelSelectorAsSelector(rs.Spec.Source.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse source selector: %w", err)
+		}
+
+		selector = s
+	}
+
+	// only source schedule backups are restored, whatever selector is set
+	r, err := labels.NewRequirement(backupsv1alpha1.ScheduleNameLabel, "=", []string{rs.Spec.Source.Name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schedule label requirement: %w", err)
+	}
+
+	opts := []client.ListOption{
+		client.InNamespace(rs.Spec.Source.GetNamespace(rs.Namespace)),
+		client.MatchingLabelsSelector{Selector: selector.Add(*r)},
+	}
+
+	backups := make([]scheduleBackup, 0)
+	switch rs.Spec.Source.Kind {
+	case backupsv1alpha1.RestoreScheduleSourceClickHouse:
+		bl := &backupsv1alpha1.ClickHouseBackupList{}
+		if err := rc.List(ctx, bl, opts...); err != nil {
+			return nil, fmt.Errorf("failed to list clickhouse backup objects: %w", err)
+		}
+
+		for i := range bl.Items {
+			b := &bl.Items[i]
+
+			backups = append(backups, scheduleBackup{obj: b, phase: b.Status.Phase})
+		}
+	case backupsv1alpha1.RestoreScheduleSourceDgraph:
+		bl := &backupsv1alpha1.DgraphBackupList{}
+		if err := rc.List(ctx, bl, opts...); err != nil {
+			return nil, fmt.Errorf("failed to list dgraph backup objects: %w", err)
+		}
+
+		for i := range bl.Items {
+			b := &bl.Items[i]
+
+			backups = append(backups, scheduleBackup{obj: b, phase: b.Status.Phase})
+		}
+	}
+
+	var newest client.Object
+	for _, b := range backups {
+		if b.phase != PhaseCompleted || !backupsv1alpha1.IsRestoreAllowed(b.obj, rs.Namespace) {
+			continue
+		}
+
+		if newest == nil || backupsv1alpha1.GetCreationTime(b.obj).After(backupsv1alpha1.GetCreationTime(newest)) {
+			newest = b.obj
+		}
+	}
+
+	if newest == nil {
+		return nil, ErrNoCompletedBackup
+	}
+
+	return newest, nil
+}
@@ -0,0 +1,129 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/notify"
+)
+
+// OwnerUIDField is backup objects field index by controller owner uid
+const OwnerUIDField = ".metadata.controller"
+
+// IndexOwnerUID registers controller owner uid field index of given backup objects kind
+func IndexOwnerUID(ctx context.Context, indexer client.FieldIndexer, obj client.Object) error {
+	return indexer.IndexField(ctx, obj, OwnerUIDField, func(o client.Object) []string {
+		owner := metav1.GetControllerOf(o)
+		if owner == nil {
+			return nil
+		}
+
+		return []string{string(owner.UID)}
+	})
+}
+
+// RemoveOutdatedClickHouseBackups deletes schedule backups older than retention.
+// Returned duration is time left until next backup expiration, zero if there is nothing to expire.
+func RemoveOutdatedClickHouseBackups(ctx context.Context, rc client.Client, l logr.Logger, n notify.Notifier, bs *backupsv1alpha1.ClickHouseBackupSchedule) (time.Duration, error) {
+	bl := &backupsv1alpha1.ClickHouseBackupList{}
+	if err := rc.List(ctx, bl, client.InNamespace(bs.Namespace), client.MatchingFields{OwnerUIDField: string(bs.UID)}); err != nil {
+		return 0, fmt.Errorf("failed to list clickhouse backup objects: %w", err)
+	}
+
+	backups := make([]scheduleBackup, 0, len(bl.Items))
+	for i := range bl.Items {
+		b := &bl.Items[i]
+
+		backups = append(backups, scheduleBackup{obj: b, phase: b.Status.Phase})
+	}
+
+	return removeOutdatedBackups(ctx, rc, l, n, EngineClickHouse, bs, bs.Spec.Retention, backups)
+}
+
+// RemoveOutdatedDgraphBackups deletes schedule backups older than retention.
+// Returned duration is time left until next backup expiration, zero if there is nothing to expire.
+func RemoveOutdatedDgraphBackups(ctx context.Context, rc client.Client, l logr.Logger, n notify.Notifier, bs *backupsv1alpha1.DgraphBackupSchedule) (time.Duration, error) {
+	bl := &backupsv1alpha1.DgraphBackupList{}
+	if err := rc.List(ctx, bl, client.InNamespace(bs.Namespace), client.MatchingFields{OwnerUIDField: string(bs.UID)}); err != nil {
+		return 0, fmt.Errorf("failed to list dgraph backup objects: %w", err)
+	}
+
+	backups := make([]scheduleBackup, 0, len(bl.Items))
+	for i := range bl.Items {
+		b := &bl.Items[i]
+
+		backups = append(backups, scheduleBackup{obj: b, phase: b.Status.Phase})
+	}
+
+	return removeOutdatedBackups(ctx, rc, l, n, EngineDgraph, bs, bs.Spec.Retention, backups)
+}
+
+func removeOutdatedBackups(ctx context.Context, rc client.Client, l logr.Logger, n notify.Notifier, engine string, schedule metav1.Object, retention string, backups []scheduleBackup) (time.Duration, error) {
+	rd, err := time.ParseDuration(retention)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse retention duration: %w", err)
+	}
+
+	var retained, deleted int
+	var next time.Duration
+	var errs []error
+	for _, b := range backups {
+		// object is already deleted, but it is kept by finalizer
+		if !b.obj.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+
+		// imported backups may be excluded from retention explicitly, so their data is not deleted
+		if backupsv1alpha1.IsRetentionExcluded(b.obj) {
+			retained++
+
+			continue
+		}
+
+		left := rd - time.Since(backupsv1alpha1.GetCreationTime(b.obj))
+		if left > 0 {
+			retained++
+
+			if next == 0 || left < next {
+				next = left
+			}
+
+			continue
+		}
+
+		l.V(3).Info("delete backup object", "name", b.obj.GetName())
+
+		if err := rc.Delete(ctx, b.obj); client.IgnoreNotFound(err) != nil {
+			retained++
+			errs = append(errs, fmt.Errorf("%s: %w", b.obj.GetName(), err))
+
+			continue
+		}
+
+		deleted++
+
+		NotifyRetentionDeleted(ctx, l, n, engine, b.obj, b.phase)
+	}
+
+	ObserveRetention(engine, schedule.GetName(), schedule.GetNamespace(), retained, deleted)
+
+	if len(errs) > 0 {
+		return next, fmt.Errorf("failed to delete backup objects: %v", errs)
+	}
+
+	return next, nil
+}
+
+func getScheduleBackupLabels(name string, uid types.UID) map[string]string {
+	return map[string]string{
+		backupsv1alpha1.ScheduleNameLabel: name,
+		backupsv1alpha1.ScheduleUIDLabel:  string(uid),
+	}
+}
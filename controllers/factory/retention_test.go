@@ -0,0 +1,170 @@
+package factory
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/metrics"
+)
+
+func newRetentionBackup(name string, age time.Duration, annotations map[string]string) *backupsv1alpha1.DgraphBackup {
+	return &backupsv1alpha1.DgraphBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "prod",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			Annotations:       annotations,
+		},
+	}
+}
+
+func TestRemoveOutdatedBackups(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := backupsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add scheme: %s", err)
+	}
+
+	deleting := newRetentionBackup("deleting", 3*time.Hour, nil)
+	deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	deleting.Finalizers = []string{backupsv1alpha1.FinalizerName}
+
+	tests := []struct {
+		name      string
+		retention string
+		backups   []*backupsv1alpha1.DgraphBackup
+		remaining []string
+		retained  int
+		next      time.Duration
+		wantErr   bool
+	}{
+		{
+			name:      "outdated backups are deleted",
+			retention: "1h",
+			backups: []*backupsv1alpha1.DgraphBackup{
+				newRetentionBackup("old", 2*time.Hour, nil),
+				newRetentionBackup("fresh", 30*time.Minute, nil),
+				newRetentionBackup("newest", 10*time.Minute, nil),
+			},
+			remaining: []string{"fresh", "newest"},
+			retained:  2,
+			next:      30 * time.Minute,
+		},
+		{
+			name:      "created at annotation is used",
+			retention: "1h",
+			backups: []*backupsv1alpha1.DgraphBackup{
+				newRetentionBackup("imported-old", time.Minute, map[string]string{
+					backupsv1alpha1.ImportedAnnotation:  "true",
+					backupsv1alpha1.CreatedAtAnnotation: time.Now().Add(-2 * time.Hour).Format(time.RFC3339),
+				}),
+				newRetentionBackup("imported-fresh", 2*time.Hour, map[string]string{
+					backupsv1alpha1.ImportedAnnotation:  "true",
+					backupsv1alpha1.CreatedAtAnnotation: time.Now().Add(-20 * time.Minute).Format(time.RFC3339),
+				}),
+			},
+			remaining: []string{"imported-fresh"},
+			retained:  1,
+			next:      40 * time.Minute,
+		},
+		{
+			name:      "excluded backups are retained",
+			retention: "1h",
+			backups: []*backupsv1alpha1.DgraphBackup{
+				newRetentionBackup("excluded", 2*time.Hour, map[string]string{backupsv1alpha1.RetentionExcludedAnnotation: "true"}),
+				newRetentionBackup("not-excluded", 2*time.Hour, map[string]string{backupsv1alpha1.RetentionExcludedAnnotation: "false"}),
+			},
+			remaining: []string{"excluded"},
+			retained:  1,
+		},
+		{
+			name:      "deleting backups are not counted",
+			retention: "1h",
+			backups: []*backupsv1alpha1.DgraphBackup{
+				deleting,
+				newRetentionBackup("fresh", 30*time.Minute, nil),
+			},
+			remaining: []string{"deleting", "fresh"},
+			retained:  1,
+			next:      30 * time.Minute,
+		},
+		{
+			name:      "no backups",
+			retention: "1h",
+		},
+		{
+			name:      "invalid retention",
+			retention: "week",
+			backups: []*backupsv1alpha1.DgraphBackup{
+				newRetentionBackup("old", 2*time.Hour, nil),
+			},
+			remaining: []string{"old"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := make([]client.Object, 0, len(tt.backups))
+			backups := make([]scheduleBackup, 0, len(tt.backups))
+			for _, b := range tt.backups {
+				b = b.DeepCopy()
+				objs = append(objs, b)
+				backups = append(backups, scheduleBackup{obj: b, phase: PhaseCompleted})
+			}
+
+			rc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+			bs := &backupsv1alpha1.DgraphBackupSchedule{ObjectMeta: metav1.ObjectMeta{Name: "retention", Namespace: "prod"}}
+
+			next, err := removeOutdatedBackups(context.Background(), rc, logr.Discard(), nil, EngineDgraph, bs, tt.retention, backups)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// next expiration is computed from time.Now, so it is compared with tolerance
+			if d := next - tt.next; d > time.Minute || d < -time.Minute {
+				t.Errorf("expected next expiration in %s, got %s", tt.next, next)
+			}
+
+			bl := &backupsv1alpha1.DgraphBackupList{}
+			if err := rc.List(context.Background(), bl); err != nil {
+				t.Fatalf("failed to list backups: %s", err)
+			}
+
+			remaining := make([]string, 0, len(bl.Items))
+			for _, b := range bl.Items {
+				remaining = append(remaining, b.Name)
+			}
+			sort.Strings(remaining)
+
+			if len(remaining) != len(tt.remaining) {
+				t.Fatalf("expected remaining backups %v, got %v", tt.remaining, remaining)
+			}
+
+			for i := range remaining {
+				if remaining[i] != tt.remaining[i] {
+					t.Fatalf("expected remaining backups %v, got %v", tt.remaining, remaining)
+				}
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			labels := prometheus.Labels{"engine": EngineDgraph, "schedule": "retention", "namespace": "prod"}
+			if retained := testutil.ToFloat64(metrics.RetainedBackupsBySchedule.With(labels)); int(retained) != tt.retained {
+				t.Errorf("expected %d retained backups, got %v", tt.retained, retained)
+			}
+		})
+	}
+}
@@ -0,0 +1,346 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/metrics"
+)
+
+const (
+	// defaultBackupNameTemplate keeps backup names format used before name templates
+	defaultBackupNameTemplate = "{{.Schedule}}-{{.Time.Unix}}"
+
+	// scheduleTickWindow is period before task start, where its schedule tick is searched
+	scheduleTickWindow = time.Minute
+)
+
+const (
+	// SkipReasonSuspended, SkipReasonBlackout and SkipReasonNameConflict are skipped schedule tick reasons
+	SkipReasonSuspended    = "Suspended"
+	SkipReasonBlackout     = "Blackout"
+	SkipReasonNameConflict = "NameConflict"
+)
+
+// NewClickHouseScheduleBackup returns backup object owned by given schedule for schedule tick at given time
+func NewClickHouseScheduleBackup(bs *backupsv1alpha1.ClickHouseBackupSchedule, tick time.Time) (*backupsv1alpha1.ClickHouseBackup, error) {
+	om, err := getScheduleBackupMeta(bs, bs.Spec.BackupTemplate, bs.Spec.TimeZone, tick)
+	if err != nil {
+		return nil, err
+	}
+
+	om.OwnerReferences = bs.AsOwner()
+
+	return &backupsv1alpha1.ClickHouseBackup{
+		ObjectMeta: om,
+		Spec:       bs.Spec.Backup,
+	}, nil
+}
+
+// NewDgraphScheduleBackup returns backup object owned by given schedule for schedule tick at given time
+func NewDgraphScheduleBackup(bs *backupsv1alpha1.DgraphBackupSchedule, tick time.Time) (*backupsv1alpha1.DgraphBackup, error) {
+	om, err := getScheduleBackupMeta(bs, bs.Spec.BackupTemplate, bs.Spec.TimeZone, tick)
+	if err != nil {
+		return nil, err
+	}
+
+	om.OwnerReferences = bs.AsOwner()
+
+	return &backupsv1alpha1.DgraphBackup{
+		ObjectMeta: om,
+		Spec:       bs.Spec.Backup,
+	}, nil
+}
+
+// jitterSchedule shifts activation times of schedule by constant offset
+type jitterSchedule struct {
+	cron.Schedule
+	offset time.Duration
+}
+
+func (s *jitterSchedule) Next(t time.Time) time.Time {
+	return s.Schedule.Next(t.Add(-s.offset)).Add(s.offset)
+}
+
+// ParseBackupSchedule parses backup creation schedule in given time zone.
+// Activation times are delayed by offset in [0, jitter), which is derived from uid, so it is stable across restarts.
+func ParseBackupSchedule(schedule, timeZone, jitter string, uid types.UID) (cron.Schedule, error) {
+	if timeZone != "" {
+		if strings.HasPrefix(schedule, "CRON_TZ=") || strings.HasPrefix(schedule, "TZ=") {
+			return nil, fmt.Errorf("time zone is set both in schedule and timeZone field")
+		}
+
+		schedule = fmt.Sprintf("CRON_TZ=%s %s", timeZone, schedule)
+	}
+
+	s, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schedule: %w", err)
+	}
+
+	if jitter == "" {
+		return s, nil
+	}
+
+	d, err := time.ParseDuration(jitter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jitter: %w", err)
+	}
+
+	if d <= 0 {
+		return s, nil
+	}
+
+	return &jitterSchedule{Schedule: s, offset: getJitterOffset(uid, d)}, nil
+}
+
+// ScheduleBackupTask schedules backup creation task, previous task with given id is removed.
+// Task gets time of schedule tick, which it is started for.
+func ScheduleBackupTask(c *cron.Cron, l logr.Logger, schedule, timeZone, jitter string, uid types.UID, id int, f func(tick time.Time)) (cron.EntryID, error) {
+	s, err := ParseBackupSchedule(schedule, timeZone, jitter, uid)
+	if err != nil {
+		return 0, err
+	}
+
+	RemoveTask(c, id)
+
+	l.V(4).Info("scheduling task", "schedule", schedule, "timeZone", timeZone, "jitter", jitter)
+
+	return c.Schedule(s, cron.FuncJob(func() { f(getScheduleTick(s, time.Now())) })), nil
+}
+
+// ValidateBackupNameTemplate checks that backup name template renders different names
+// for two consecutive schedule ticks, so every tick creates its own backup object.
+func ValidateBackupNameTemplate(obj metav1.Object, template *backupsv1alpha1.BackupTemplate, schedule, timeZone, jitter string) error {
+	s, err := ParseBackupSchedule(schedule, timeZone, jitter, obj.GetUID())
+	if err != nil {
+		return err
+	}
+
+	tick := s.Next(time.Now())
+	first, err := getScheduleBackupMeta(obj, template, timeZone, tick)
+	if err != nil {
+		return err
+	}
+
+	second, err := getScheduleBackupMeta(obj, template, timeZone, s.Next(tick))
+	if err != nil {
+		return err
+	}
+
+	if first.Name == second.Name {
+		return fmt.Errorf("backup name template renders same name %q for consecutive schedule ticks", first.Name)
+	}
+
+	return nil
+}
+
+// IsScheduleBackupNameConflict checks if existing object with name of given backup is created before given tick,
+// so it belongs to previous tick and backup for this tick can not be created.
+// Object created after tick is backup of this tick created by another operator replica.
+func IsScheduleBackupNameConflict(ctx context.Context, rc client.Client, b client.Object, tick time.Time) (bool, error) {
+	existing, ok := b.DeepCopyObject().(client.Object)
+	if !ok {
+		return false, fmt.Errorf("unexpected backup object type %T", b)
+	}
+
+	if err := rc.Get(ctx, client.ObjectKeyFromObject(b), existing); err != nil {
+		return false, fmt.Errorf("failed to get existing backup object: %w", err)
+	}
+
+	return existing.GetCreationTimestamp().Time.Before(tick.Truncate(time.Second)), nil
+}
+
+// GetPendingTrigger returns trigger annotation value, if it is not handled yet
+func GetPendingTrigger(obj metav1.Object, lastTrigger string) (string, bool) {
+	value := obj.GetAnnotations()[backupsv1alpha1.TriggerAnnotation]
+	if value == "" || value == lastTrigger {
+		return "", false
+	}
+
+	return value, true
+}
+
+// GetTriggeredBackupName returns backup name with suffix derived from trigger value,
+// so triggered backup does not collide with scheduled one created at the same time
+func GetTriggeredBackupName(name, trigger string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(trigger))
+
+	return fmt.Sprintf("%s-%08x", name, h.Sum32())
+}
+
+// GetSkipReason returns reason to skip schedule tick at given time, empty if backup should be created
+func GetSkipReason(suspend bool, windows []backupsv1alpha1.BlackoutWindow, now time.Time) (string, error) {
+	if suspend {
+		return SkipReasonSuspended, nil
+	}
+
+	for i := range windows {
+		active, err := windows[i].IsActive(now)
+		if err != nil {
+			return "", err
+		}
+
+		if active {
+			return SkipReasonBlackout, nil
+		}
+	}
+
+	return "", nil
+}
+
+// SkipClickHouseScheduleTick records skipped tick in schedule status
+func SkipClickHouseScheduleTick(ctx context.Context, rc client.Client, key types.NamespacedName, reason string) error {
+	bs := &backupsv1alpha1.ClickHouseBackupSchedule{}
+	if err := rc.Get(ctx, key, bs); err != nil {
+		return fmt.Errorf("failed to get clickhouse backup schedule object: %w", err)
+	}
+
+	bs.Status.Skipped = getSkipStatus(bs.Status.Skipped, reason)
+	observeSkippedTick(EngineClickHouse, bs, reason)
+
+	if err := rc.Status().Update(ctx, bs); err != nil {
+		return fmt.Errorf("failed update clickhouse backup schedule object: %w", err)
+	}
+
+	return nil
+}
+
+// SkipDgraphScheduleTick records skipped tick in schedule status
+func SkipDgraphScheduleTick(ctx context.Context, rc client.Client, key types.NamespacedName, reason string) error {
+	bs := &backupsv1alpha1.DgraphBackupSchedule{}
+	if err := rc.Get(ctx, key, bs); err != nil {
+		return fmt.Errorf("failed to get dgraph backup schedule object: %w", err)
+	}
+
+	bs.Status.Skipped = getSkipStatus(bs.Status.Skipped, reason)
+	observeSkippedTick(EngineDgraph, bs, reason)
+
+	if err := rc.Status().Update(ctx, bs); err != nil {
+		return fmt.Errorf("failed update dgraph backup schedule object: %w", err)
+	}
+
+	return nil
+}
+
+func getSkipStatus(current *backupsv1alpha1.BackupSkipStatus, reason string) *backupsv1alpha1.BackupSkipStatus {
+	status := &backupsv1alpha1.BackupSkipStatus{}
+	if current != nil {
+		status.Count = current.Count
+	}
+
+	status.Count++
+	status.LastSkippedTime = metav1.Now()
+	status.Reason = reason
+
+	return status
+}
+
+func observeSkippedTick(engine string, obj metav1.Object, reason string) {
+	metrics.SkippedTicksBySchedule.With(
+		prometheus.Labels{
+			"engine":    engine,
+			"schedule":  obj.GetName(),
+			"namespace": obj.GetNamespace(),
+			"reason":    reason,
+		},
+	).Inc()
+}
+
+func getJitterOffset(uid types.UID, jitter time.Duration) time.Duration {
+	if jitter < time.Second {
+		return 0
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(uid))
+
+	// offset is rounded to seconds, because schedules have seconds precision
+	return time.Duration(h.Sum64()%uint64(jitter/time.Second)) * time.Second
+}
+
+// getScheduleTick returns latest schedule activation time, which is not after now.
+// Cron starts task right after activation, so activation is searched in short period before now.
+func getScheduleTick(s cron.Schedule, now time.Time) time.Time {
+	tick := now.Truncate(time.Second)
+	for t := s.Next(now.Add(-scheduleTickWindow)); !t.IsZero() && !t.After(now); t = s.Next(t) {
+		tick = t
+	}
+
+	return tick
+}
+
+// scheduleBackupNameData is backup name template data
+type scheduleBackupNameData struct {
+	Schedule  string
+	Namespace string
+	Time      time.Time
+}
+
+// getScheduleBackupMeta returns metadata of backup object created by schedule.
+// Name is rendered from template with tick time, so backups created for the same tick by several
+// operator replicas have the same name and only one of them is created.
+func getScheduleBackupMeta(obj metav1.Object, template *backupsv1alpha1.BackupTemplate, timeZone string, tick time.Time) (metav1.ObjectMeta, error) {
+	om := metav1.ObjectMeta{
+		Namespace:   obj.GetNamespace(),
+		Labels:      make(map[string]string),
+		Annotations: make(map[string]string),
+	}
+
+	nameTemplate := defaultBackupNameTemplate
+	if template != nil {
+		for k, v := range template.Metadata.Labels {
+			om.Labels[k] = v
+		}
+
+		for k, v := range template.Metadata.Annotations {
+			om.Annotations[k] = v
+		}
+
+		nameTemplate = getValueOrDefault(template.NameTemplate, defaultBackupNameTemplate)
+	}
+
+	// schedule labels are used by retention and history, so they can not be overridden
+	for k, v := range getScheduleBackupLabels(obj.GetName(), obj.GetUID()) {
+		om.Labels[k] = v
+	}
+
+	if timeZone != "" {
+		loc, err := time.LoadLocation(timeZone)
+		if err != nil {
+			return om, fmt.Errorf("failed to load time zone: %w", err)
+		}
+
+		tick = tick.In(loc)
+	}
+
+	data := scheduleBackupNameData{
+		Schedule:  obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Time:      tick,
+	}
+
+	name, err := renderTemplateString(nameTemplate, data)
+	if err != nil {
+		return om, fmt.Errorf("failed to render backup name template: %w", err)
+	}
+
+	om.Name = getObjectName(name)
+	if om.Name == "" {
+		return om, fmt.Errorf("backup name template %q gives empty name", nameTemplate)
+	}
+
+	return om, nil
+}
@@ -0,0 +1,314 @@
+package factory
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	// time zones database is embedded, so tests don't depend on system time zones
+	_ "time/tzdata"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+)
+
+func TestValidateBackupNameTemplate(t *testing.T) {
+	bs := &backupsv1alpha1.ClickHouseBackupSchedule{
+		ObjectMeta: metav1.ObjectMeta{Name: "daily", Namespace: "prod", UID: "f6a9a4a0-5d3c-4f5e-9a43-0c2b8a0b6d51"},
+	}
+
+	tests := []struct {
+		name         string
+		schedule     string
+		nameTemplate string
+		wantErr      bool
+	}{
+		{name: "default template", schedule: "0 * * * *"},
+		{name: "date template with daily schedule", schedule: "0 3 * * *", nameTemplate: `{{.Schedule}}-{{.Time.Format "20060102"}}`},
+		{name: "date template with hourly schedule", schedule: "0 * * * *", nameTemplate: `{{.Schedule}}-{{.Time.Format "20060102"}}`, wantErr: true},
+		{name: "constant template", schedule: "0 3 * * *", nameTemplate: "{{.Schedule}}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := &backupsv1alpha1.BackupTemplate{NameTemplate: tt.nameTemplate}
+
+			err := ValidateBackupNameTemplate(bs, template, tt.schedule, "", "")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestIsScheduleBackupNameConflict(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := backupsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add scheme: %s", err)
+	}
+
+	tick := time.Date(2023, 11, 17, 3, 0, 0, 0, time.UTC)
+	rc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&backupsv1alpha1.ClickHouseBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "previous", Namespace: "prod", CreationTimestamp: metav1.NewTime(tick.Add(-time.Hour))},
+		},
+		&backupsv1alpha1.ClickHouseBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "replica", Namespace: "prod", CreationTimestamp: metav1.NewTime(tick.Add(time.Second))},
+		},
+	).Build()
+
+	tests := []struct {
+		name     string
+		conflict bool
+	}{
+		{name: "previous", conflict: true},
+		{name: "replica", conflict: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &backupsv1alpha1.ClickHouseBackup{ObjectMeta: metav1.ObjectMeta{Name: tt.name, Namespace: "prod"}}
+
+			conflict, err := IsScheduleBackupNameConflict(context.Background(), rc, b, tick.Add(500*time.Millisecond))
+			if err != nil {
+				t.Fatalf("failed to check conflict: %s", err)
+			}
+
+			if conflict != tt.conflict {
+				t.Errorf("expected conflict %t, got %t", tt.conflict, conflict)
+			}
+		})
+	}
+}
+
+func TestGetJitterOffset(t *testing.T) {
+	tests := []struct {
+		name   string
+		uid    types.UID
+		jitter time.Duration
+	}{
+		{name: "sub second jitter", uid: "f6a9a4a0-5d3c-4f5e-9a43-0c2b8a0b6d51", jitter: 500 * time.Millisecond},
+		{name: "minute jitter", uid: "f6a9a4a0-5d3c-4f5e-9a43-0c2b8a0b6d51", jitter: time.Minute},
+		{name: "hour jitter", uid: "0c1d7b9e-2a61-4a8e-b2f4-7e3f1c9a5d20", jitter: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset := getJitterOffset(tt.uid, tt.jitter)
+
+			if tt.jitter < time.Second && offset != 0 {
+				t.Errorf("expected zero offset, got %s", offset)
+			}
+
+			if offset < 0 || (offset > 0 && offset >= tt.jitter) || offset%time.Second != 0 {
+				t.Errorf("offset %s is out of [0, %s) or is not rounded to seconds", offset, tt.jitter)
+			}
+
+			// offset is derived from uid only, so it is the same after operator restart
+			for i := 0; i < 3; i++ {
+				if o := getJitterOffset(tt.uid, tt.jitter); o != offset {
+					t.Fatalf("offset is not deterministic: %s != %s", o, offset)
+				}
+			}
+		})
+	}
+
+	if getJitterOffset("schedule-a", time.Hour) == getJitterOffset("schedule-b", time.Hour) {
+		t.Error("expected different offsets of different schedules")
+	}
+}
+
+func TestParseBackupSchedule(t *testing.T) {
+	uid := types.UID("f6a9a4a0-5d3c-4f5e-9a43-0c2b8a0b6d51")
+	now := time.Date(2023, 11, 17, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule string
+		timeZone string
+		jitter   string
+		next     time.Time
+		wantErr  bool
+	}{
+		{name: "utc", schedule: "0 3 * * *", next: time.Date(2023, 11, 18, 3, 0, 0, 0, time.UTC)},
+		{name: "time zone field", schedule: "0 3 * * *", timeZone: "Europe/Moscow", next: time.Date(2023, 11, 18, 0, 0, 0, 0, time.UTC)},
+		{name: "time zone in schedule", schedule: "CRON_TZ=Europe/Moscow 0 3 * * *", next: time.Date(2023, 11, 18, 0, 0, 0, 0, time.UTC)},
+		{name: "CRON_TZ conflicts with time zone field", schedule: "CRON_TZ=Europe/Moscow 0 3 * * *", timeZone: "Europe/Moscow", wantErr: true},
+		{name: "TZ conflicts with time zone field", schedule: "TZ=UTC 0 3 * * *", timeZone: "Europe/Moscow", wantErr: true},
+		{name: "unknown time zone", schedule: "0 3 * * *", timeZone: "Mars/Olympus", wantErr: true},
+		{name: "invalid schedule", schedule: "0 3 * *", wantErr: true},
+		{name: "invalid jitter", schedule: "0 3 * * *", jitter: "soon", wantErr: true},
+		{name: "negative jitter", schedule: "0 3 * * *", jitter: "-1m", next: time.Date(2023, 11, 18, 3, 0, 0, 0, time.UTC)},
+		{name: "jitter", schedule: "0 3 * * *", jitter: "1h", next: time.Date(2023, 11, 18, 3, 0, 0, 0, time.UTC).Add(getJitterOffset(uid, time.Hour))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseBackupSchedule(tt.schedule, tt.timeZone, tt.jitter, uid)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected parse error: %v", err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if next := s.Next(now); !next.Equal(tt.next) {
+				t.Errorf("expected next activation %s, got %s", tt.next, next)
+			}
+		})
+	}
+}
+
+func TestGetScheduleTick(t *testing.T) {
+	uid := types.UID("f6a9a4a0-5d3c-4f5e-9a43-0c2b8a0b6d51")
+	activation := time.Date(2023, 11, 17, 3, 0, 0, 0, time.UTC)
+	offset := getJitterOffset(uid, time.Hour)
+
+	tests := []struct {
+		name   string
+		jitter string
+		now    time.Time
+		tick   time.Time
+	}{
+		{name: "started on time", now: activation.Add(300 * time.Millisecond), tick: activation},
+		{name: "started late", now: activation.Add(40 * time.Second), tick: activation},
+		{name: "started out of window", now: activation.Add(5*time.Minute + 300*time.Millisecond), tick: activation.Add(5 * time.Minute)},
+		{name: "jitter", jitter: "1h", now: activation.Add(offset + time.Second), tick: activation.Add(offset)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseBackupSchedule("0 3 * * *", "", tt.jitter, uid)
+			if err != nil {
+				t.Fatalf("failed to parse schedule: %s", err)
+			}
+
+			if tick := getScheduleTick(s, tt.now); !tick.Equal(tt.tick) {
+				t.Errorf("expected tick %s, got %s", tt.tick, tick)
+			}
+		})
+	}
+
+	// every second schedule has many activations in window, latest one is used
+	s, err := cron.ParseStandard("@every 10s")
+	if err != nil {
+		t.Fatalf("failed to parse schedule: %s", err)
+	}
+
+	now := time.Now()
+	if tick := getScheduleTick(s, now); tick.After(now) || now.Sub(tick) > 10*time.Second {
+		t.Errorf("unexpected tick %s of every 10s schedule at %s", tick, now)
+	}
+}
+
+func TestGetSkipReason(t *testing.T) {
+	now := time.Date(2023, 11, 17, 3, 0, 0, 0, time.UTC)
+	hourAgo := metav1.NewTime(now.Add(-time.Hour))
+	hourLater := metav1.NewTime(now.Add(time.Hour))
+
+	tests := []struct {
+		name    string
+		suspend bool
+		windows []backupsv1alpha1.BlackoutWindow
+		reason  string
+		wantErr bool
+	}{
+		{name: "no windows"},
+		{name: "suspended", suspend: true, windows: []backupsv1alpha1.BlackoutWindow{{Start: &hourAgo, End: &hourLater}}, reason: SkipReasonSuspended},
+		{name: "inside fixed window", windows: []backupsv1alpha1.BlackoutWindow{{Start: &hourAgo, End: &hourLater}}, reason: SkipReasonBlackout},
+		{name: "after fixed window", windows: []backupsv1alpha1.BlackoutWindow{{Start: &hourAgo, End: &hourAgo}}},
+		{name: "fixed window end is excluded", windows: []backupsv1alpha1.BlackoutWindow{{Start: &hourAgo, End: &metav1.Time{Time: now}}}},
+		{name: "window started now", windows: []backupsv1alpha1.BlackoutWindow{{Schedule: "0 3 * * *", Duration: "1h"}}, reason: SkipReasonBlackout},
+		{name: "inside scheduled window", windows: []backupsv1alpha1.BlackoutWindow{{Schedule: "0 2 * * *", Duration: "2h"}}, reason: SkipReasonBlackout},
+		{name: "after scheduled window", windows: []backupsv1alpha1.BlackoutWindow{{Schedule: "0 1 * * *", Duration: "2h"}}},
+		{name: "before scheduled window", windows: []backupsv1alpha1.BlackoutWindow{{Schedule: "0 4 * * *", Duration: "2h"}}},
+		{name: "scheduled window time zone", windows: []backupsv1alpha1.BlackoutWindow{{Schedule: "0 6 * * *", Duration: "30m", TimeZone: "Europe/Moscow"}}, reason: SkipReasonBlackout},
+		{name: "second window is active", windows: []backupsv1alpha1.BlackoutWindow{{Schedule: "0 4 * * *", Duration: "1h"}, {Start: &hourAgo, End: &hourLater}}, reason: SkipReasonBlackout},
+		{name: "window without end", windows: []backupsv1alpha1.BlackoutWindow{{Start: &hourAgo}}, wantErr: true},
+		{name: "window without duration", windows: []backupsv1alpha1.BlackoutWindow{{Schedule: "0 2 * * *"}}, wantErr: true},
+		{name: "invalid window schedule", windows: []backupsv1alpha1.BlackoutWindow{{Schedule: "0 2 * *", Duration: "1h"}}, wantErr: true},
+		{name: "unknown window time zone", windows: []backupsv1alpha1.BlackoutWindow{{Schedule: "0 2 * * *", Duration: "1h", TimeZone: "Mars/Olympus"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, err := GetSkipReason(tt.suspend, tt.windows, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if reason != tt.reason {
+				t.Errorf("expected reason %q, got %q", tt.reason, reason)
+			}
+		})
+	}
+}
+
+func TestGetScheduleBackupMeta(t *testing.T) {
+	bs := &backupsv1alpha1.ClickHouseBackupSchedule{
+		ObjectMeta: metav1.ObjectMeta{Name: "daily", Namespace: "prod", UID: "f6a9a4a0-5d3c-4f5e-9a43-0c2b8a0b6d51"},
+	}
+	tick := time.Date(2023, 11, 17, 3, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		template *backupsv1alpha1.BackupTemplate
+		timeZone string
+		expected string
+		wantErr  bool
+	}{
+		{name: "without template", expected: "daily-" + strconv.FormatInt(tick.Unix(), 10)},
+		{name: "empty name template", template: &backupsv1alpha1.BackupTemplate{}, expected: "daily-" + strconv.FormatInt(tick.Unix(), 10)},
+		{name: "time format", template: &backupsv1alpha1.BackupTemplate{NameTemplate: `{{.Schedule}}-{{.Time.Format "20060102-1504"}}`}, expected: "daily-20231117-0300"},
+		{name: "schedule time zone", template: &backupsv1alpha1.BackupTemplate{NameTemplate: `{{.Schedule}}-{{.Time.Format "20060102-1504"}}`}, timeZone: "Europe/Moscow", expected: "daily-20231117-0600"},
+		{name: "invalid characters", template: &backupsv1alpha1.BackupTemplate{NameTemplate: `{{.Namespace}}_{{.Schedule}}_{{.Time.Format "Jan"}}`}, expected: "prod-daily-nov"},
+		{name: "empty name", template: &backupsv1alpha1.BackupTemplate{NameTemplate: "--"}, wantErr: true},
+		{name: "invalid template", template: &backupsv1alpha1.BackupTemplate{NameTemplate: "{{.Schedule"}, wantErr: true},
+		{name: "unknown field", template: &backupsv1alpha1.BackupTemplate{NameTemplate: "{{.Cluster}}"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			om, err := getScheduleBackupMeta(bs, tt.template, tt.timeZone, tick)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if om.Name != tt.expected || om.Namespace != "prod" {
+				t.Errorf("expected name %q in prod namespace, got %q in %q", tt.expected, om.Name, om.Namespace)
+			}
+		})
+	}
+
+	template := &backupsv1alpha1.BackupTemplate{
+		Metadata: backupsv1alpha1.BackupTemplateMetadata{
+			Labels:      map[string]string{"team": "data", backupsv1alpha1.ScheduleNameLabel: "other"},
+			Annotations: map[string]string{"owner": "data-team"},
+		},
+	}
+
+	om, err := getScheduleBackupMeta(bs, template, "", tick)
+	if err != nil {
+		t.Fatalf("failed to get backup metadata: %s", err)
+	}
+
+	if om.Labels["team"] != "data" || om.Annotations["owner"] != "data-team" {
+		t.Errorf("template metadata is not copied: %+v", om)
+	}
+
+	// schedule labels are used by retention, so template can not override them
+	if om.Labels[backupsv1alpha1.ScheduleNameLabel] != "daily" || om.Labels[backupsv1alpha1.ScheduleUIDLabel] != string(bs.UID) {
+		t.Errorf("schedule labels are overridden: %+v", om.Labels)
+	}
+}
@@ -0,0 +1,87 @@
+package factory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+)
+
+// deferredTemplateFields is fields, which are templates rendered later by schedule, so they are kept as is
+var deferredTemplateFields = map[string]bool{
+	"nameTemplate": true,
+}
+
+// renderTemplate executes go template in each string field of in and stores result into out
+func renderTemplate(in, out interface{}, data interface{}) error {
+	raw, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template: %w", err)
+	}
+
+	var fields interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return fmt.Errorf("failed to unmarshal template: %w", err)
+	}
+
+	fields, err = renderTemplateFields(fields, data)
+	if err != nil {
+		return err
+	}
+
+	raw, err = json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rendered template: %w", err)
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("failed to unmarshal rendered template: %w", err)
+	}
+
+	return nil
+}
+
+func renderTemplateFields(value interface{}, data interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return renderTemplateString(v, data)
+	case map[string]interface{}:
+		for key, item := range v {
+			if deferredTemplateFields[key] {
+				continue
+			}
+
+			rendered, err := renderTemplateFields(item, data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+
+			v[key] = rendered
+		}
+	case []interface{}:
+		for i, item := range v {
+			rendered, err := renderTemplateFields(item, data)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+
+			v[i] = rendered
+		}
+	}
+
+	return value, nil
+}
+
+func renderTemplateString(text string, data interface{}) (string, error) {
+	t, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err := t.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return out.String(), nil
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/robfig/cron/v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/controllers/factory"
+	"github.com/sputnik-systems/backups-operator/controllers/factory/finalize"
+	"github.com/sputnik-systems/backups-operator/internal/metrics"
+	"github.com/sputnik-systems/backups-operator/internal/tracing"
+)
+
+// RestoreScheduleReconciler reconciles a RestoreSchedule object
+type RestoreScheduleReconciler struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	Cron      *cron.Cron
+	StartedAt metav1.Time
+}
+
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=restoreschedules,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=restoreschedules/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=backups.sputnik.systems,resources=restoreschedules/finalizers,verbs=update
+
+// Reconcile schedules creation of restore objects for newest completed backup of source schedule
+// and keeps restore objects history
+func (r *RestoreScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "RestoreSchedule.Reconcile", tracing.Object(req.Name, req.Namespace)...)
+	defer func() { tracing.End(span, err) }()
+
+	l := log.FromContext(ctx)
+
+	l.V(1).Info("started resource reconclie")
+
+	rs := &backupsv1alpha1.RestoreSchedule{}
+	err = r.Get(ctx, req.NamespacedName, rs)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		l.Error(err, "failed to get restore schedule object for reconclie")
+
+		return ctrl.Result{}, err
+	}
+
+	if !rs.DeletionTimestamp.IsZero() {
+		factory.RemoveTask(r.Cron, rs.Status.ScheduleTaskID)
+
+		if err := finalize.RemoveFinalizeObjByName(ctx, r.Client, rs, rs.Name, rs.Namespace); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if rs.IsNeedUpdate(&r.StartedAt) {
+		if err := finalize.AddFinalizer(ctx, r.Client, rs); err != nil {
+			l.Error(err, "failed to add finalizer")
+
+			return ctrl.Result{}, err
+		}
+
+		if err := factory.CheckRestoreScheduleSpec(rs); err != nil {
+			l.Error(err, "failed to check restore schedule spec")
+
+			return ctrl.Result{}, err
+		}
+
+		createRestoreFunc := func(tick time.Time) {
+			l.V(3).Info("executing restore create schedule", "tick", tick)
+
+			if rs.Spec.Suspend {
+				l.V(3).Info("skipping restore creation", "reason", factory.SkipReasonSuspended)
+
+				return
+			}
+
+			obj, err := factory.NewScheduleRestore(ctx, r.Client, rs, tick)
+			if err != nil {
+				if errors.Is(err, factory.ErrNoCompletedBackup) || errors.Is(err, factory.ErrRestoreInProgress) {
+					l.V(1).Info("skipping restore creation", "reason", err.Error())
+
+					if err := factory.SetRestoreScheduleError(ctx, r.Client, req.NamespacedName, err); err != nil {
+						l.Error(err, "failed to record skipped restore")
+					}
+
+					return
+				}
+
+				metrics.ScheduledTaskFailuresByControllerTotal.With(
+					prometheus.Labels{
+						"name":       rs.Name,
+						"namespace":  rs.Namespace,
+						"controller": "restoreschedule",
+						"action":     "create",
+					},
+				).Inc()
+
+				l.Error(err, "failed to get restore object")
+
+				return
+			}
+
+			l.V(3).Info("creating restore object", "name", obj.GetName())
+
+			if err := r.Create(ctx, obj); err != nil {
+				// restore for this tick is already created by another operator replica
+				if apierrors.IsAlreadyExists(err) {
+					l.V(3).Info("restore object already exists", "name", obj.GetName())
+
+					return
+				}
+
+				metrics.ScheduledTaskFailuresByControllerTotal.With(
+					prometheus.Labels{
+						"name":       rs.Name,
+						"namespace":  rs.Namespace,
+						"controller": "restoreschedule",
+						"action":     "create",
+					},
+				).Inc()
+
+				l.Error(err, "failed to create restore object")
+
+				return
+			}
+
+			if err := factory.SetRestoreScheduleError(ctx, r.Client, req.NamespacedName, nil); err != nil {
+				l.Error(err, "failed to clear restore schedule error")
+			}
+		}
+
+		l.V(2).Info("schedule restore creation task")
+
+		id, err := factory.ScheduleBackupTask(r.Cron, l.WithValues("action", "create"), rs.Spec.Schedule, rs.Spec.TimeZone, "", rs.UID, rs.Status.ScheduleTaskID, createRestoreFunc)
+		if err != nil {
+			l.Error(err, "failed to schedule restore")
+
+			return ctrl.Result{}, err
+		}
+
+		rs.Status.ScheduleTaskID = int(id)
+		rs.Status.ActiveGeneration = rs.Generation
+		rs.Status.UpdatedAt = metav1.Now()
+		if err := r.Status().Update(ctx, rs); err != nil {
+			l.Error(err, "failed update restore schedule object")
+
+			return ctrl.Result{}, err
+		}
+	}
+
+	// owned restores changes trigger reconcile, so history is kept current
+	changed, err := factory.UpdateRestoreScheduleHistory(ctx, r.Client, rs)
+	if err != nil {
+		metrics.ScheduledTaskFailuresByControllerTotal.With(
+			prometheus.Labels{
+				"name":       rs.Name,
+				"namespace":  rs.Namespace,
+				"controller": "restoreschedule",
+				"action":     "remove",
+			},
+		).Inc()
+
+		l.Error(err, "failed to update restore schedule history")
+
+		return ctrl.Result{}, err
+	}
+
+	if changed {
+		if err := r.Status().Update(ctx, rs); err != nil {
+			l.Error(err, "failed update restore schedule object")
+
+			return ctrl.Result{}, err
+		}
+	}
+
+	l.V(1).Info("finished resource reconclie")
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RestoreScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := factory.IndexOwnerUID(context.Background(), mgr.GetFieldIndexer(), &backupsv1alpha1.ClickHouseRestore{}); err != nil {
+		return err
+	}
+
+	if err := factory.IndexOwnerUID(context.Background(), mgr.GetFieldIndexer(), &backupsv1alpha1.DgraphRestore{}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&backupsv1alpha1.RestoreSchedule{}).
+		Owns(&backupsv1alpha1.ClickHouseRestore{}).
+		Owns(&backupsv1alpha1.DgraphRestore{}).
+		Complete(r)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// monitoring generates PrometheusRule and grafana dashboard from operator metrics
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/sputnik-systems/backups-operator/internal/monitoring"
+)
+
+func main() {
+	var rulesPath, dashboardPath string
+	flag.StringVar(&rulesPath, "rules", "config/prometheus/rules.yaml", "PrometheusRule output file path.")
+	flag.StringVar(&dashboardPath, "dashboard", "config/grafana/dashboard.json", "Grafana dashboard output file path.")
+	flag.Parse()
+
+	if err := run(rulesPath, dashboardPath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(rulesPath, dashboardPath string) error {
+	rule, err := monitoring.PrometheusRule("backups-operator-rules", "system", monitoring.Rules())
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(rule.Object)
+	if err != nil {
+		return fmt.Errorf("failed to marshal prometheus rule: %w", err)
+	}
+
+	if err := ioutil.WriteFile(rulesPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write prometheus rule: %w", err)
+	}
+
+	data, err = monitoring.Dashboard()
+	if err != nil {
+		return fmt.Errorf("failed to generate dashboard: %w", err)
+	}
+
+	if err := ioutil.WriteFile(dashboardPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write dashboard: %w", err)
+	}
+
+	return nil
+}
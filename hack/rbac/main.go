@@ -0,0 +1,149 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// rbac generates namespaced Roles and RoleBindings from manager ClusterRole
+// for operator started with --watch-namespaces flag
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// clusterResources is list of cluster scoped resources, which can't be granted by Role
+var clusterResources = []string{"namespaces", "clusterbackupschedules", "backuppolicies"}
+
+func main() {
+	var rolePath, outPath, namespaces, serviceAccount, serviceAccountNamespace string
+	var secrets bool
+	flag.StringVar(&rolePath, "role", "config/rbac/role.yaml", "Manager ClusterRole file path.")
+	flag.StringVar(&outPath, "out", "-", "Output file path, stdout if -.")
+	flag.StringVar(&namespaces, "namespaces", "", "Comma separated list of watched namespaces.")
+	flag.StringVar(&serviceAccount, "service-account", "backups-operator-backups-operator", "Operator service account name.")
+	flag.StringVar(&serviceAccountNamespace, "service-account-namespace", "backups-system", "Operator service account namespace.")
+	flag.BoolVar(&secrets, "secrets", true, "Grant secrets read, disable it, when credentials are read with impersonated service account.")
+	flag.Parse()
+
+	if err := run(rolePath, outPath, strings.Split(namespaces, ","), serviceAccount, serviceAccountNamespace, secrets); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(rolePath, outPath string, namespaces []string, serviceAccount, serviceAccountNamespace string, secrets bool) error {
+	data, err := ioutil.ReadFile(rolePath)
+	if err != nil {
+		return fmt.Errorf("failed to read cluster role: %w", err)
+	}
+
+	cr := &rbacv1.ClusterRole{}
+	if err := yaml.Unmarshal(data, cr); err != nil {
+		return fmt.Errorf("failed to unmarshal cluster role: %w", err)
+	}
+
+	rules := make([]rbacv1.PolicyRule, 0, len(cr.Rules))
+	for _, rule := range cr.Rules {
+		resources := make([]string, 0, len(rule.Resources))
+		for _, resource := range rule.Resources {
+			if !isClusterResource(resource) {
+				resources = append(resources, resource)
+			}
+		}
+
+		if len(resources) > 0 {
+			rule.Resources = resources
+			rules = append(rules, rule)
+		}
+	}
+
+	if secrets {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{""},
+			Resources: []string{"secrets"},
+			Verbs:     []string{"get", "list", "watch"},
+		})
+	}
+
+	var out bytes.Buffer
+	for _, ns := range namespaces {
+		if ns == "" {
+			continue
+		}
+
+		role := &rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Name: "backups-operator-manager-role", Namespace: ns},
+			Rules:      rules,
+		}
+
+		binding := &rbacv1.RoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "RoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: "backups-operator-manager-rolebinding", Namespace: ns},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "Role",
+				Name:     role.Name,
+			},
+			Subjects: []rbacv1.Subject{
+				{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      serviceAccount,
+					Namespace: serviceAccountNamespace,
+				},
+			},
+		}
+
+		for _, obj := range []interface{}{role, binding} {
+			data, err := yaml.Marshal(obj)
+			if err != nil {
+				return fmt.Errorf("failed to marshal %s rbac: %w", ns, err)
+			}
+
+			out.WriteString("---\n")
+			out.Write(data)
+		}
+	}
+
+	if outPath == "-" {
+		_, err := os.Stdout.Write(out.Bytes())
+
+		return err
+	}
+
+	if err := ioutil.WriteFile(outPath, out.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write rbac: %w", err)
+	}
+
+	return nil
+}
+
+func isClusterResource(resource string) bool {
+	for _, item := range clusterResources {
+		if resource == item || strings.HasPrefix(resource, item+"/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -3,16 +3,54 @@ package clickhouse
 import (
 	"bufio"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/AlexAkulov/clickhouse-backup/pkg/server"
+	"go.opentelemetry.io/otel/attribute"
 
-	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/tracing"
 )
 
+const (
+	LocationLocal  = "local"
+	LocationRemote = "remote"
+
+	OperationCreate   = "create"
+	OperationUpload   = "upload"
+	OperationDownload = "download"
+	OperationRestore  = "restore"
+	OperationDelete   = "delete"
+
+	StatusInProgress = "in progress"
+	StatusSuccess    = "success"
+	StatusError      = "error"
+)
+
+// defaultTimeout is used when request timeout is not specified
+const defaultTimeout = 30 * time.Second
+
+// idleConnTimeout limits how long unused keep-alive connections of tls transports are kept open
+const idleConnTimeout = 90 * time.Second
+
+// transports are shared by clients with the same tls settings,
+// because clients are created on every reconcile and each transport keeps own idle connections
+var (
+	transportsMu sync.Mutex
+	transports   = make(map[string]*http.Transport)
+)
+
+// timeLayout is clickhouse-backup time format
+const timeLayout = "2006-01-02 15:04:05"
+
 type Backup struct {
 	Name           string `json:"name"`
 	Created        string `json:"created"`
@@ -22,105 +60,420 @@ type Backup struct {
 	Desc           string `json:"desc"`
 }
 
-func CreateBackup(ctx context.Context, b *backupsv1alpha1.ClickHouseBackup) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodPost, b.Status.Api.Address+"/backup/create", nil)
+// CreatedTime returns parsed backup creation time
+func (b Backup) CreatedTime() (time.Time, error) {
+	return time.Parse(timeLayout, b.Created)
+}
+
+// ActionRow is clickhouse-backup operation log entry
+type ActionRow struct {
+	Command     string `json:"command"`
+	Status      string `json:"status"`
+	Start       string `json:"start,omitempty"`
+	Finish      string `json:"finish,omitempty"`
+	Error       string `json:"error,omitempty"`
+	OperationID string `json:"operation_id,omitempty"`
+}
+
+// IsMatch checks if row describes given operation on backup with given name.
+// Command is compared by words, so backup "db-1" does not match "db-10".
+func (r ActionRow) IsMatch(operation, name string) bool {
+	fields := strings.Fields(r.Command)
+	if len(fields) < 2 {
+		return false
+	}
+
+	return fields[0] == operation && strings.Trim(fields[len(fields)-1], `"`) == name
+}
+
+// Duration returns operation execution duration
+func (r ActionRow) Duration() (time.Duration, error) {
+	start, err := time.Parse(timeLayout, r.Start)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse start time: %w", err)
+	}
+
+	finish, err := time.Parse(timeLayout, r.Finish)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate backup creation request: %s", err)
+		return 0, fmt.Errorf("failed to parse finish time: %w", err)
+	}
+
+	return finish.Sub(start), nil
+}
+
+// Acknowledgement is clickhouse-backup response for asynchronous operations
+type Acknowledgement struct {
+	Status      string `json:"status"`
+	Operation   string `json:"operation"`
+	BackupName  string `json:"backup_name"`
+	OperationID string `json:"operation_id,omitempty"`
+}
+
+// APIError is returned when clickhouse-backup responds with unexpected status code
+type APIError struct {
+	StatusCode int
+	Operation  string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("clickhouse-backup api responded with status code %d: %s", e.StatusCode, e.Message)
+	}
+
+	return fmt.Sprintf("clickhouse-backup api responded with status code %d", e.StatusCode)
+}
+
+// IsLocked checks if request was rejected, because another operation is in progress
+func IsLocked(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusLocked
+	}
+
+	return false
+}
+
+// Client is clickhouse-backup api client
+type Client struct {
+	address    string
+	hostname   string
+	username   string
+	password   string
+	timeout    time.Duration
+	transport  http.RoundTripper
+	httpClient *http.Client
+}
+
+// Option configures Client
+type Option func(*Client)
+
+// WithBasicAuth sets API_USERNAME and API_PASSWORD credentials
+func WithBasicAuth(username, password string) Option {
+	return func(c *Client) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// WithTLSConfig sets tls settings for https api address.
+// Clients with the same key share one transport, so key must identify given tls settings.
+func WithTLSConfig(key string, config *tls.Config) Option {
+	return func(c *Client) {
+		c.transport = getTransport(key, config)
+	}
+}
+
+// getTransport returns transport for given tls settings key, it is created on first call
+func getTransport(key string, config *tls.Config) *http.Transport {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+
+	if t, ok := transports[key]; ok {
+		return t
+	}
+
+	t := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		TLSClientConfig:     config,
+		TLSHandshakeTimeout: 10 * time.Second,
+		IdleConnTimeout:     idleConnTimeout,
+	}
+	transports[key] = t
+
+	return t
+}
+
+// WithTimeout sets single request timeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		if timeout > 0 {
+			c.timeout = timeout
+		}
+	}
+}
+
+// WithHostname sets Host header value, it is useful when address contains ip
+func WithHostname(hostname string) Option {
+	return func(c *Client) {
+		c.hostname = hostname
+	}
+}
+
+// NewClient returns clickhouse-backup api client for given address
+func NewClient(address string, opts ...Option) *Client {
+	c := &Client{
+		address: strings.TrimSuffix(address, "/"),
+		timeout: defaultTimeout,
 	}
 
-	q := req.URL.Query()
-	for key, value := range b.Spec.CreateParams {
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	// requests are traced and propagate trace context to clickhouse-backup
+	c.httpClient = &http.Client{Transport: tracing.Transport(c.transport)}
+
+	return c
+}
+
+// CreateBackup starts backup creation
+func (c *Client) CreateBackup(ctx context.Context, name string, params map[string]string) (ack *Acknowledgement, err error) {
+	ctx, span := tracing.Start(ctx, "clickhouse.CreateBackup", attribute.String("backup.name", name))
+	defer func() { tracing.End(span, err) }()
+
+	q := url.Values{}
+	for key, value := range params {
 		q.Add(key, value)
 	}
-	q.Add("name", b.Name)
-	req.URL.RawQuery = q.Encode()
+	q.Set("name", name)
+
+	ack = &Acknowledgement{}
+	if err := c.do(ctx, http.MethodPost, "/backup/create", q, ack); err != nil {
+		return nil, fmt.Errorf("failed to create backup: %w", err)
+	}
 
-	return http.DefaultClient.Do(req)
+	return ack, nil
 }
 
-func UploadBackup(ctx context.Context, b *backupsv1alpha1.ClickHouseBackup) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodPost, b.Status.Api.Address+"/backup/upload/"+b.Name, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate backup uploading request: %s", err)
+// UploadBackup starts backup uploading to remote storage
+func (c *Client) UploadBackup(ctx context.Context, name string, params map[string]string) (ack *Acknowledgement, err error) {
+	ctx, span := tracing.Start(ctx, "clickhouse.UploadBackup", attribute.String("backup.name", name))
+	defer func() { tracing.End(span, err) }()
+
+	q := url.Values{}
+	for key, value := range params {
+		q.Add(key, value)
+	}
+
+	ack = &Acknowledgement{}
+	if err := c.do(ctx, http.MethodPost, "/backup/upload/"+url.PathEscape(name), q, ack); err != nil {
+		return nil, fmt.Errorf("failed to upload backup: %w", err)
+	}
+
+	return ack, nil
+}
+
+// DownloadBackup starts backup downloading from remote storage
+func (c *Client) DownloadBackup(ctx context.Context, name string, params map[string]string) (ack *Acknowledgement, err error) {
+	ctx, span := tracing.Start(ctx, "clickhouse.DownloadBackup", attribute.String("backup.name", name))
+	defer func() { tracing.End(span, err) }()
+
+	q := url.Values{}
+	for key, value := range params {
+		q.Add(key, value)
 	}
 
-	q := req.URL.Query()
-	for key, value := range b.Spec.CreateParams {
+	ack = &Acknowledgement{}
+	if err := c.do(ctx, http.MethodPost, "/backup/download/"+url.PathEscape(name), q, ack); err != nil {
+		return nil, fmt.Errorf("failed to download backup: %w", err)
+	}
+
+	return ack, nil
+}
+
+// RestoreBackup starts restoring of downloaded backup
+func (c *Client) RestoreBackup(ctx context.Context, name string, params map[string]string) (ack *Acknowledgement, err error) {
+	ctx, span := tracing.Start(ctx, "clickhouse.RestoreBackup", attribute.String("backup.name", name))
+	defer func() { tracing.End(span, err) }()
+
+	q := url.Values{}
+	for key, value := range params {
 		q.Add(key, value)
 	}
-	req.URL.RawQuery = q.Encode()
 
-	return http.DefaultClient.Do(req)
+	ack = &Acknowledgement{}
+	if err := c.do(ctx, http.MethodPost, "/backup/restore/"+url.PathEscape(name), q, ack); err != nil {
+		return nil, fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	return ack, nil
+}
+
+// DeleteBackup removes backup from given location
+func (c *Client) DeleteBackup(ctx context.Context, location, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "clickhouse.DeleteBackup", attribute.String("backup.name", name), attribute.String("backup.location", location))
+	defer func() { tracing.End(span, err) }()
+
+	if err := c.do(ctx, http.MethodPost, "/backup/delete/"+location+"/"+url.PathEscape(name), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete %s backup: %w", location, err)
+	}
+
+	return nil
+}
+
+// ListBackups returns all local and remote backups known by clickhouse-backup instance
+func (c *Client) ListBackups(ctx context.Context) (_ []Backup, err error) {
+	ctx, span := tracing.Start(ctx, "clickhouse.ListBackups")
+	defer func() { tracing.End(span, err) }()
+
+	backups := make([]Backup, 0)
+	err = c.doEachRow(ctx, "/backup/list", nil, func(data []byte) error {
+		var backup Backup
+		if err := json.Unmarshal(data, &backup); err != nil {
+			return fmt.Errorf("failed to unmarshal backup: %w", err)
+		}
+
+		backups = append(backups, backup)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	return backups, nil
 }
 
-func DeleteBackup(ctx context.Context, b *backupsv1alpha1.ClickHouseBackup) (*http.Response, error) {
-	backups, err := listBackups(ctx, b)
+// GetActions returns operations log filtered by given substring
+func (c *Client) GetActions(ctx context.Context, filter string) ([]ActionRow, error) {
+	q := url.Values{}
+	if filter != "" {
+		q.Set("filter", filter)
+	}
+
+	rows := make([]ActionRow, 0)
+	err := c.doEachRow(ctx, "/backup/actions", q, func(data []byte) error {
+		var row ActionRow
+		if err := json.Unmarshal(data, &row); err != nil {
+			return fmt.Errorf("failed to unmarshal action row: %w", err)
+		}
+
+		rows = append(rows, row)
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list backups: %s", err)
+		return nil, fmt.Errorf("failed to get actions: %w", err)
 	}
 
-	for _, backup := range backups {
-		switch backup.Location {
-		case "local":
-			if resp, err := http.Post(b.Spec.ApiAddress+"/backup/delete/local/"+b.Name, "application/json", nil); err != nil {
-				return resp, fmt.Errorf("failed to delete local backup: %s", err)
-			}
-		case "remote":
-			if resp, err := http.Post(b.Spec.ApiAddress+"/backup/delete/remote/"+b.Name, "application/json", nil); err != nil {
-				return resp, fmt.Errorf("failed to delete remote backup: %s", err)
+	return rows, nil
+}
+
+// GetOperation returns last log entry of given operation.
+// If operation id is known it is used for matching, otherwise operation and backup name are compared.
+// Nil row is returned, if operation not found.
+func (c *Client) GetOperation(ctx context.Context, operation, name, operationID string) (_ *ActionRow, err error) {
+	ctx, span := tracing.Start(ctx, "clickhouse.GetOperation", attribute.String("backup.name", name), attribute.String("backup.operation", operation))
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := c.GetActions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(rows) - 1; i >= 0; i-- {
+		row := rows[i]
+
+		if operationID != "" && row.OperationID != "" {
+			if row.OperationID == operationID {
+				return &row, nil
 			}
+
+			continue
+		}
+
+		if row.IsMatch(operation, name) {
+			return &row, nil
 		}
 	}
 
 	return nil, nil
 }
 
-func GetStatus(ctx context.Context, b *backupsv1alpha1.ClickHouseBackup) ([]server.ActionRow, error) {
-	resp, err := http.Get(b.Status.Api.Address + "/backup/status")
+func (c *Client) do(ctx context.Context, method, path string, q url.Values, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.request(ctx, method, path, q)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get status: %s", err)
+		return err
 	}
+	defer resp.Body.Close()
 
-	scanner := bufio.NewScanner(resp.Body)
+	if out == nil {
+		_, err = io.Copy(ioutil.Discard, resp.Body)
+
+		return err
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) doEachRow(ctx context.Context, path string, q url.Values, f func([]byte) error) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.request(ctx, http.MethodGet, path, q)
+	if err != nil {
+		return err
+	}
 	defer resp.Body.Close()
 
-	rows := make([]server.ActionRow, 0)
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
 	for scanner.Scan() {
-		var row server.ActionRow
-		err := json.Unmarshal(scanner.Bytes(), &row)
-		if err != nil {
-			return nil, fmt.Errorf("failed to unmarshal action row: %s", err)
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
 		}
 
-		if strings.Contains(row.Command, b.Name) {
-			rows = append(rows, row)
+		if err := f(scanner.Bytes()); err != nil {
+			return err
 		}
 	}
 
-	return rows, nil
+	return scanner.Err()
 }
 
-func listBackups(ctx context.Context, b *backupsv1alpha1.ClickHouseBackup) ([]Backup, error) {
-	resp, err := http.Get(b.Spec.ApiAddress + "/backup/list")
+// request sends request and checks response status code.
+// Response body must be closed by caller, if error is not returned.
+func (c *Client) request(ctx context.Context, method, path string, q url.Values) (*http.Response, error) {
+	u := c.address + path
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get status: %s", err)
+		return nil, fmt.Errorf("failed to generate request: %w", err)
 	}
 
-	scanner := bufio.NewScanner(resp.Body)
-	defer resp.Body.Close()
+	if c.hostname != "" {
+		req.Host = c.hostname
+	}
 
-	backups := make([]Backup, 0)
-	for scanner.Scan() {
-		var backup Backup
-		err := json.Unmarshal(scanner.Bytes(), &backup)
-		if err != nil {
-			return nil, fmt.Errorf("failed to unmarshal backup: %s", err)
-		}
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
 
-		if backup.Name == b.Name {
-			backups = append(backups, backup)
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+
+		var body struct {
+			Operation string `json:"operation"`
+			Error     string `json:"error"`
+		}
+		data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		if err := json.Unmarshal(data, &body); err == nil {
+			apiErr.Operation = body.Operation
+			apiErr.Message = body.Error
+		} else {
+			apiErr.Message = strings.TrimSpace(string(data))
 		}
+
+		return nil, apiErr
 	}
 
-	return backups, nil
+	return resp, nil
 }
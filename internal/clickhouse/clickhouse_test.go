@@ -0,0 +1,141 @@
+package clickhouse_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/sputnik-systems/backups-operator/internal/clickhouse"
+	"github.com/sputnik-systems/backups-operator/internal/clickhouse/clickhousetest"
+)
+
+func TestClientGetOperationMatchesExactName(t *testing.T) {
+	s := clickhousetest.NewServer()
+	defer s.Close()
+
+	s.Errors["db-10"] = "disk is full"
+
+	ctx := context.Background()
+	c := clickhouse.NewClient(s.URL)
+
+	for _, name := range []string{"db-1", "db-10"} {
+		if _, err := c.CreateBackup(ctx, name, nil); err != nil {
+			t.Fatalf("failed to create backup %q: %s", name, err)
+		}
+	}
+
+	row, err := c.GetOperation(ctx, clickhouse.OperationCreate, "db-1", "")
+	if err != nil {
+		t.Fatalf("failed to get operation: %s", err)
+	}
+
+	if row == nil || row.Status != clickhouse.StatusSuccess {
+		t.Fatalf("expected successful db-1 creation, got %+v", row)
+	}
+
+	row, err = c.GetOperation(ctx, clickhouse.OperationUpload, "db-1", "")
+	if err != nil {
+		t.Fatalf("failed to get operation: %s", err)
+	}
+
+	if row != nil {
+		t.Fatalf("expected upload operation not found, got %+v", row)
+	}
+}
+
+func TestClientUploadParams(t *testing.T) {
+	s := clickhousetest.NewServer()
+	defer s.Close()
+
+	ctx := context.Background()
+	c := clickhouse.NewClient(s.URL)
+
+	if _, err := c.CreateBackup(ctx, "db", map[string]string{"table": "default.*"}); err != nil {
+		t.Fatalf("failed to create backup: %s", err)
+	}
+
+	if _, err := c.UploadBackup(ctx, "db", map[string]string{"diff-from": "db-0"}); err != nil {
+		t.Fatalf("failed to upload backup: %s", err)
+	}
+
+	if got := s.Query("/backup/create").Get("table"); got != "default.*" {
+		t.Errorf("expected create table param %q, got %q", "default.*", got)
+	}
+
+	q := s.Query("/backup/upload/db")
+	if got := q.Get("diff-from"); got != "db-0" {
+		t.Errorf("expected upload diff-from param %q, got %q", "db-0", got)
+	}
+
+	if got := q.Get("table"); got != "" {
+		t.Errorf("expected create params are not used by upload, got table %q", got)
+	}
+}
+
+func TestClientRestoreParams(t *testing.T) {
+	s := clickhousetest.NewServer()
+	defer s.Close()
+
+	s.AddBackup(clickhouse.Backup{Name: "db", Location: clickhouse.LocationRemote})
+
+	ctx := context.Background()
+	c := clickhouse.NewClient(s.URL)
+
+	if _, err := c.RestoreBackup(ctx, "db", nil); err == nil {
+		t.Fatal("expected error for restore of not downloaded backup")
+	}
+
+	if _, err := c.DownloadBackup(ctx, "db", nil); err != nil {
+		t.Fatalf("failed to download backup: %s", err)
+	}
+
+	if _, err := c.RestoreBackup(ctx, "db", map[string]string{"restore_database_mapping": "prod:staging"}); err != nil {
+		t.Fatalf("failed to restore backup: %s", err)
+	}
+
+	if got := s.Query("/backup/restore/db").Get("restore_database_mapping"); got != "prod:staging" {
+		t.Errorf("expected restore database mapping param %q, got %q", "prod:staging", got)
+	}
+
+	row, err := c.GetOperation(ctx, clickhouse.OperationRestore, "db", "")
+	if err != nil {
+		t.Fatalf("failed to get operation: %s", err)
+	}
+
+	if row == nil || row.Status != clickhouse.StatusSuccess {
+		t.Fatalf("expected successful db restore, got %+v", row)
+	}
+}
+
+func TestClientStatusCodes(t *testing.T) {
+	s := clickhousetest.NewServer()
+	defer s.Close()
+
+	s.Username = "backup"
+	s.Password = "secret"
+
+	ctx := context.Background()
+
+	_, err := clickhouse.NewClient(s.URL).ListBackups(ctx)
+
+	var apiErr *clickhouse.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized error, got %v", err)
+	}
+
+	c := clickhouse.NewClient(s.URL, clickhouse.WithBasicAuth("backup", "secret"))
+	if _, err := c.ListBackups(ctx); err != nil {
+		t.Fatalf("failed to list backups: %s", err)
+	}
+
+	s.Locked = true
+	if _, err := c.CreateBackup(ctx, "db", nil); !clickhouse.IsLocked(err) {
+		t.Fatalf("expected locked error, got %v", err)
+	}
+
+	s.Locked = false
+	if err := c.DeleteBackup(ctx, clickhouse.LocationRemote, "unknown"); err == nil {
+		t.Fatal("expected error on missing backup deletion")
+	}
+}
@@ -0,0 +1,293 @@
+// Package clickhousetest provides in-memory clickhouse-backup api stand-in for tests.
+package clickhousetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sputnik-systems/backups-operator/internal/clickhouse"
+)
+
+// Server is clickhouse-backup api stand-in.
+// All operations are finished synchronously.
+type Server struct {
+	*httptest.Server
+
+	// Username and Password enables basic auth check, if set
+	Username string
+	Password string
+
+	// Locked makes server reject all operations with 423 status code
+	Locked bool
+
+	// Errors is map of backup names to error messages, operations on these backups will fail
+	Errors map[string]string
+
+	mu      sync.Mutex
+	backups []clickhouse.Backup
+	actions []clickhouse.ActionRow
+	queries map[string]url.Values
+}
+
+// NewServer starts new clickhouse-backup api stand-in
+func NewServer() *Server {
+	s := &Server{
+		Errors:  make(map[string]string),
+		queries: make(map[string]url.Values),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/backup/create", s.post(s.create))
+	mux.HandleFunc("/backup/upload/", s.post(s.upload))
+	mux.HandleFunc("/backup/download/", s.post(s.download))
+	mux.HandleFunc("/backup/restore/", s.post(s.restore))
+	mux.HandleFunc("/backup/delete/", s.post(s.delete))
+	mux.HandleFunc("/backup/list", s.list)
+	mux.HandleFunc("/backup/actions", s.listActions)
+
+	s.Server = httptest.NewServer(s.auth(mux))
+
+	return s
+}
+
+// AddBackup adds backup to server state
+func (s *Server) AddBackup(b clickhouse.Backup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if b.Created == "" {
+		b.Created = time.Now().UTC().Format("2006-01-02 15:04:05")
+	}
+
+	s.backups = append(s.backups, b)
+}
+
+// Backups returns current server backups
+func (s *Server) Backups() []clickhouse.Backup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]clickhouse.Backup(nil), s.backups...)
+}
+
+// Query returns query params of last request to given path
+func (s *Server) Query(path string) url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.queries[path]
+}
+
+func (s *Server) auth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.Username != "" || s.Password != "" {
+			username, password, _ := r.BasicAuth()
+			if username != s.Username || password != s.Password {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprintln(w, "401 Unauthorized")
+
+				return
+			}
+		}
+
+		s.mu.Lock()
+		s.queries[r.URL.Path] = r.URL.Query()
+		s.mu.Unlock()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) post(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "", "405 Method Not Allowed")
+
+			return
+		}
+
+		if s.Locked {
+			writeError(w, http.StatusLocked, "", "another operation is currently running")
+
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.finish("create "+name, name) {
+		s.backups = append(s.backups, clickhouse.Backup{
+			Name:     name,
+			Created:  time.Now().UTC().Format("2006-01-02 15:04:05"),
+			Location: clickhouse.LocationLocal,
+		})
+	}
+
+	writeJSON(w, http.StatusCreated, clickhouse.Acknowledgement{Status: "acknowledged", Operation: clickhouse.OperationCreate, BackupName: name})
+}
+
+func (s *Server) upload(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/backup/upload/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.find(clickhouse.LocationLocal, name) < 0 {
+		writeError(w, http.StatusInternalServerError, clickhouse.OperationUpload, fmt.Sprintf("backup %q not found", name))
+
+		return
+	}
+
+	if s.finish("upload "+name, name) {
+		s.backups = append(s.backups, clickhouse.Backup{
+			Name:     name,
+			Created:  time.Now().UTC().Format("2006-01-02 15:04:05"),
+			Location: clickhouse.LocationRemote,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, clickhouse.Acknowledgement{Status: "acknowledged", Operation: clickhouse.OperationUpload, BackupName: name})
+}
+
+func (s *Server) download(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/backup/download/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.find(clickhouse.LocationRemote, name) < 0 {
+		writeError(w, http.StatusInternalServerError, clickhouse.OperationDownload, fmt.Sprintf("backup %q not found", name))
+
+		return
+	}
+
+	if s.finish("download "+name, name) && s.find(clickhouse.LocationLocal, name) < 0 {
+		s.backups = append(s.backups, clickhouse.Backup{
+			Name:     name,
+			Created:  time.Now().UTC().Format("2006-01-02 15:04:05"),
+			Location: clickhouse.LocationLocal,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, clickhouse.Acknowledgement{Status: "acknowledged", Operation: clickhouse.OperationDownload, BackupName: name})
+}
+
+func (s *Server) restore(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/backup/restore/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.find(clickhouse.LocationLocal, name) < 0 {
+		writeError(w, http.StatusInternalServerError, clickhouse.OperationRestore, fmt.Sprintf("backup %q not found", name))
+
+		return
+	}
+
+	s.finish("restore "+name, name)
+
+	writeJSON(w, http.StatusOK, clickhouse.Acknowledgement{Status: "acknowledged", Operation: clickhouse.OperationRestore, BackupName: name})
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/backup/delete/"), "/", 2)
+	if len(parts) != 2 {
+		writeError(w, http.StatusNotFound, "", "404 Not Found")
+
+		return
+	}
+
+	location, name := parts[0], parts[1]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.find(location, name)
+	if i < 0 {
+		writeError(w, http.StatusInternalServerError, clickhouse.OperationDelete, fmt.Sprintf("backup %q not found", name))
+
+		return
+	}
+
+	s.backups = append(s.backups[:i], s.backups[i+1:]...)
+	s.actions = append(s.actions, clickhouse.ActionRow{Command: "delete " + location + " " + name, Status: clickhouse.StatusSuccess})
+
+	writeJSON(w, http.StatusOK, clickhouse.Acknowledgement{Status: clickhouse.StatusSuccess, Operation: clickhouse.OperationDelete, BackupName: name})
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	enc := json.NewEncoder(w)
+	for _, b := range s.Backups() {
+		_ = enc.Encode(b)
+	}
+}
+
+func (s *Server) listActions(w http.ResponseWriter, r *http.Request) {
+	filter := r.URL.Query().Get("filter")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enc := json.NewEncoder(w)
+	for _, row := range s.actions {
+		if strings.Contains(row.Command, filter) {
+			_ = enc.Encode(row)
+		}
+	}
+}
+
+// finish records finished operation and returns true if it was successful
+func (s *Server) finish(command, name string) bool {
+	row := clickhouse.ActionRow{Command: command, Status: clickhouse.StatusSuccess}
+	if msg, ok := s.Errors[name]; ok {
+		row.Status = clickhouse.StatusError
+		row.Error = msg
+	}
+
+	s.actions = append(s.actions, row)
+
+	return row.Status == clickhouse.StatusSuccess
+}
+
+func (s *Server) find(location, name string) int {
+	for i, b := range s.backups {
+		if b.Location == location && b.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(code)
+
+	out, _ := json.Marshal(v)
+	fmt.Fprintln(w, string(out))
+}
+
+func writeError(w http.ResponseWriter, code int, operation, msg string) {
+	writeJSON(w, code, struct {
+		Status    string `json:"status"`
+		Operation string `json:"operation,omitempty"`
+		Error     string `json:"error"`
+	}{
+		Status:    clickhouse.StatusError,
+		Operation: operation,
+		Error:     msg,
+	})
+}
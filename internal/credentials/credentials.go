@@ -0,0 +1,162 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+const (
+	// EnvAccessKey, EnvSecretKey and EnvSessionToken are default environment credentials variables
+	EnvAccessKey    = "AWS_ACCESS_KEY_ID"
+	EnvSecretKey    = "AWS_SECRET_ACCESS_KEY"
+	EnvSessionToken = "AWS_SESSION_TOKEN"
+
+	// EnvWebIdentityTokenFile and EnvRoleARN are default web identity variables set by IRSA
+	EnvWebIdentityTokenFile = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	EnvRoleARN              = "AWS_ROLE_ARN"
+)
+
+// Credentials is remote storage credentials
+type Credentials struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+// Provider returns remote storage credentials
+type Provider interface {
+	Retrieve(ctx context.Context) (Credentials, error)
+}
+
+// Static provides fixed credentials
+type Static struct {
+	Credentials Credentials
+}
+
+func (p *Static) Retrieve(_ context.Context) (Credentials, error) {
+	return p.Credentials, nil
+}
+
+// File provides credentials from files mounted into operator pod
+type File struct {
+	AccessKeyPath    string
+	SecretKeyPath    string
+	SessionTokenPath string
+}
+
+func (p *File) Retrieve(_ context.Context) (Credentials, error) {
+	var creds Credentials
+	var err error
+
+	if creds.AccessKey, err = readFile(p.AccessKeyPath, true); err != nil {
+		return creds, fmt.Errorf("failed to read access key: %w", err)
+	}
+
+	if creds.SecretKey, err = readFile(p.SecretKeyPath, true); err != nil {
+		return creds, fmt.Errorf("failed to read secret key: %w", err)
+	}
+
+	if creds.SessionToken, err = readFile(p.SessionTokenPath, false); err != nil {
+		return creds, fmt.Errorf("failed to read session token: %w", err)
+	}
+
+	return creds, nil
+}
+
+// Env provides credentials from operator environment variables, AWS_* variables are used by default
+type Env struct {
+	AccessKeyVar    string
+	SecretKeyVar    string
+	SessionTokenVar string
+}
+
+func (p *Env) Retrieve(_ context.Context) (Credentials, error) {
+	creds := Credentials{
+		AccessKey:    os.Getenv(valueOrDefault(p.AccessKeyVar, EnvAccessKey)),
+		SecretKey:    os.Getenv(valueOrDefault(p.SecretKeyVar, EnvSecretKey)),
+		SessionToken: os.Getenv(valueOrDefault(p.SessionTokenVar, EnvSessionToken)),
+	}
+
+	if creds.AccessKey == "" {
+		return creds, fmt.Errorf("environment variable %s is not set", valueOrDefault(p.AccessKeyVar, EnvAccessKey))
+	}
+
+	if creds.SecretKey == "" {
+		return creds, fmt.Errorf("environment variable %s is not set", valueOrDefault(p.SecretKeyVar, EnvSecretKey))
+	}
+
+	return creds, nil
+}
+
+// WebIdentity provides temporary credentials exchanged for web identity token, as IRSA does.
+// Token file and role are taken from AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN variables by default.
+type WebIdentity struct {
+	RoleARN     string
+	TokenFile   string
+	SessionName string
+	Region      string
+}
+
+func (p *WebIdentity) Retrieve(ctx context.Context) (Credentials, error) {
+	roleARN := valueOrDefault(p.RoleARN, os.Getenv(EnvRoleARN))
+	if roleARN == "" {
+		return Credentials{}, errors.New("web identity role arn is not set")
+	}
+
+	tokenFile := valueOrDefault(p.TokenFile, os.Getenv(EnvWebIdentityTokenFile))
+	if tokenFile == "" {
+		return Credentials{}, errors.New("web identity token file is not set")
+	}
+
+	sess, err := session.NewSession(aws.NewConfig().WithRegion(p.Region))
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to create sts session: %w", err)
+	}
+
+	provider := stscreds.NewWebIdentityRoleProvider(sts.New(sess), roleARN, valueOrDefault(p.SessionName, "backups-operator"), tokenFile)
+
+	value, err := provider.RetrieveWithContext(ctx)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to exchange web identity token: %w", err)
+	}
+
+	return Credentials{
+		AccessKey:    value.AccessKeyID,
+		SecretKey:    value.SecretAccessKey,
+		SessionToken: value.SessionToken,
+	}, nil
+}
+
+func readFile(path string, required bool) (string, error) {
+	if path == "" {
+		if required {
+			return "", errors.New("file path is not set")
+		}
+
+		return "", nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+func valueOrDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+
+	return value
+}
@@ -0,0 +1,70 @@
+package credentials_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sputnik-systems/backups-operator/internal/credentials"
+)
+
+func TestFileRetrieve(t *testing.T) {
+	dir := t.TempDir()
+	for name, value := range map[string]string{"access": "id\n", "secret": "key\n"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(value), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := &credentials.File{
+		AccessKeyPath: filepath.Join(dir, "access"),
+		SecretKeyPath: filepath.Join(dir, "secret"),
+	}
+
+	creds, err := p.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if creds.AccessKey != "id" || creds.SecretKey != "key" || creds.SessionToken != "" {
+		t.Errorf("unexpected credentials: %+v", creds)
+	}
+
+	p.SecretKeyPath = filepath.Join(dir, "missing")
+	if _, err := p.Retrieve(context.Background()); err == nil {
+		t.Error("expected error for missing secret key file")
+	}
+}
+
+func TestEnvRetrieve(t *testing.T) {
+	os.Setenv("TEST_ACCESS_KEY", "id")
+	defer os.Unsetenv("TEST_ACCESS_KEY")
+
+	p := &credentials.Env{AccessKeyVar: "TEST_ACCESS_KEY", SecretKeyVar: "TEST_SECRET_KEY"}
+	if _, err := p.Retrieve(context.Background()); err == nil {
+		t.Error("expected error for unset secret key variable")
+	}
+
+	os.Setenv("TEST_SECRET_KEY", "key")
+	defer os.Unsetenv("TEST_SECRET_KEY")
+
+	creds, err := p.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if creds.AccessKey != "id" || creds.SecretKey != "key" {
+		t.Errorf("unexpected credentials: %+v", creds)
+	}
+}
+
+func TestWebIdentityRetrieveRequiresRole(t *testing.T) {
+	os.Unsetenv(credentials.EnvRoleARN)
+
+	p := &credentials.WebIdentity{TokenFile: "/nonexistent"}
+	if _, err := p.Retrieve(context.Background()); err == nil {
+		t.Error("expected error without role arn")
+	}
+}
@@ -3,18 +3,26 @@ package dgraph
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"net/url"
 	"path"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/hasura/go-graphql-client"
+	"go.opentelemetry.io/otel/attribute"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"github.com/aws/aws-sdk-go/aws/credentials"
+	awscredentials "github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/sputnik-systems/backups-storage"
 	"github.com/sputnik-systems/backups-storage/s3"
 
 	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/credentials"
+	"github.com/sputnik-systems/backups-operator/internal/tracing"
 )
 
 type ExportOutput struct {
@@ -26,7 +34,28 @@ type ExportOutput struct {
 	ExportedFiles []graphql.String
 }
 
-func Export(ctx context.Context, rc client.Client, bs *backupsv1alpha1.DgraphBackupSpec, creds map[string]string) (*ExportOutput, error) {
+// ExportInfo is dgraph export found in remote storage
+type ExportInfo struct {
+	// Name is export directory name
+	Name string
+
+	// Files is exported files paths relative to destination
+	Files []string
+
+	// ModTime is last modification time of export files
+	ModTime time.Time
+
+	// Size is total size of export files in bytes
+	Size int64
+}
+
+// exportDirPrefix is prefix of directories created by dgraph export
+const exportDirPrefix = "dgraph."
+
+func Export(ctx context.Context, rc client.Client, bs *backupsv1alpha1.DgraphBackupSpec, creds credentials.Credentials) (_ *ExportOutput, err error) {
+	ctx, span := tracing.Start(ctx, "dgraph.Export", attribute.String("dgraph.destination", bs.Destination))
+	defer func() { tracing.End(span, err) }()
+
 	type ExportInput struct {
 		Format      graphql.String `json:"format"`
 		Destination graphql.String `json:"destination"`
@@ -39,9 +68,8 @@ func Export(ctx context.Context, rc client.Client, bs *backupsv1alpha1.DgraphBac
 		Destination: graphql.String(bs.Destination),
 	}
 
-	id, secret, _ := parseCredentials(creds)
-	input.AccessKey = graphql.String(id)
-	input.SecretKey = graphql.String(secret)
+	input.AccessKey = graphql.String(creds.AccessKey)
+	input.SecretKey = graphql.String(creds.SecretKey)
 
 	gqlVars := map[string]interface{}{
 		"input": input,
@@ -51,8 +79,9 @@ func Export(ctx context.Context, rc client.Client, bs *backupsv1alpha1.DgraphBac
 		ExportOutput `graphql:"export(input: $input)"`
 	}
 
-	client := graphql.NewClient(bs.AdminUrl, nil)
-	err := client.Mutate(context.Background(), &gqlMutation, gqlVars)
+	// requests are traced and propagate trace context to dgraph alpha
+	client := graphql.NewClient(bs.AdminUrl, &http.Client{Transport: tracing.Transport(nil)})
+	err = client.Mutate(ctx, &gqlMutation, gqlVars)
 	if err != nil {
 		return nil, err
 	}
@@ -60,54 +89,142 @@ func Export(ctx context.Context, rc client.Client, bs *backupsv1alpha1.DgraphBac
 	return &gqlMutation.ExportOutput, nil
 }
 
-func DeleteExport(ctx context.Context, b *backupsv1alpha1.DgraphBackup, creds map[string]string) error {
+func DeleteExport(ctx context.Context, b *backupsv1alpha1.DgraphBackup, creds credentials.Credentials) (err error) {
+	ctx, span := tracing.Start(ctx, "dgraph.DeleteExport", tracing.Object(b.Name, b.Namespace)...)
+	defer func() { tracing.End(span, err) }()
+
 	if len(b.Status.ExportResponse.ExportedFiles) == 0 {
 		return errors.New("export info not exists")
 	}
 
+	return DeleteExportDir(ctx, &b.Spec, creds, GetExportDir(b))
+}
+
+// DeleteExportDir removes export directory from destination s3 storage
+func DeleteExportDir(ctx context.Context, bs *backupsv1alpha1.DgraphBackupSpec, creds credentials.Credentials, dir string) (err error) {
+	_, span := tracing.Start(ctx, "storage.Delete", attribute.String("dgraph.destination", bs.Destination), attribute.String("storage.path", dir))
+	defer func() { tracing.End(span, err) }()
+
+	storage, _, err := newStorage(bs.Destination, bs.Region, creds)
+	if err != nil {
+		return err
+	}
+
+	return storage.Delete(dir)
+}
+
+// GetExportDir returns export directory name of given backup
+func GetExportDir(b *backupsv1alpha1.DgraphBackup) string {
+	if len(b.Status.ExportResponse.ExportedFiles) == 0 {
+		return ""
+	}
+
+	return path.Dir(b.Status.ExportResponse.ExportedFiles[0])
+}
+
+// GetExportSize returns total size of given backup export files in destination s3 storage
+func GetExportSize(ctx context.Context, b *backupsv1alpha1.DgraphBackup, creds credentials.Credentials) (int64, error) {
+	dir := path.Base(GetExportDir(b))
+	if dir == "" || dir == "." {
+		return 0, errors.New("export info not exists")
+	}
+
+	exports, err := ListExports(ctx, &b.Spec, creds)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, export := range exports {
+		if export.Name == dir {
+			return export.Size, nil
+		}
+	}
+
+	return 0, fmt.Errorf("export %q not found in destination", dir)
+}
+
+// ListExports returns exports found in destination s3 storage
+func ListExports(ctx context.Context, bs *backupsv1alpha1.DgraphBackupSpec, creds credentials.Credentials) (_ []ExportInfo, err error) {
+	_, span := tracing.Start(ctx, "storage.List", attribute.String("dgraph.destination", bs.Destination))
+	defer func() { tracing.End(span, err) }()
+
+	storage, prefix, err := newStorage(bs.Destination, bs.Region, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := storage.List()
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make(map[string]*ExportInfo)
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		name := strings.TrimPrefix(strings.TrimPrefix(file.Name(), prefix), "/")
+		dir := strings.Split(name, "/")[0]
+		if !strings.HasPrefix(dir, exportDirPrefix) || dir == name {
+			continue
+		}
+
+		export, ok := exports[dir]
+		if !ok {
+			export = &ExportInfo{Name: dir}
+			exports[dir] = export
+		}
+
+		export.Files = append(export.Files, name)
+		export.Size += file.Size()
+		if file.ModTime().After(export.ModTime) {
+			export.ModTime = file.ModTime()
+		}
+	}
+
+	out := make([]ExportInfo, 0, len(exports))
+	for _, export := range exports {
+		out = append(out, *export)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+
+	return out, nil
+}
+
+func newStorage(destination, region string, creds credentials.Credentials) (storage.Storage, string, error) {
 	opts := session.Options{
 		SharedConfigState: session.SharedConfigEnable,
 	}
 
 	sess, err := session.NewSessionWithOptions(opts)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
-	u, err := url.Parse(b.Spec.Destination)
+	u, err := url.Parse(destination)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
 	endpoint := u.Hostname()
 	uri := strings.Split(u.RequestURI(), "/")
 
-	id, secret, token := parseCredentials(creds)
 	sess.Config.WithEndpoint(endpoint)
-	sess.Config.WithRegion(b.Spec.Region)
+	sess.Config.WithRegion(region)
 	sess.Config.WithS3ForcePathStyle(true)
-	sess.Config.WithCredentials(
-		credentials.NewStaticCredentials(id, secret, token))
-
-	bucket := uri[1]
-	prefix := path.Join(uri[2:]...)
-	backup := path.Dir(b.Status.ExportResponse.ExportedFiles[0])
-	storage := s3.NewStorage(sess, bucket, prefix)
-	return storage.Delete(backup)
-}
-
-func parseCredentials(creds map[string]string) (id, secret, token string) {
-	if value, ok := creds["accessKey"]; ok {
-		id = value
-	}
 
-	if value, ok := creds["secretKey"]; ok {
-		secret = value
+	// default credentials chain is used, when credentials are not configured
+	if creds.AccessKey != "" {
+		sess.Config.WithCredentials(
+			awscredentials.NewStaticCredentials(creds.AccessKey, creds.SecretKey, creds.SessionToken))
 	}
 
-	if value, ok := creds["sessionToken"]; ok {
-		token = value
-	}
+	bucket := uri[1]
+	prefix := path.Join(uri[2:]...)
 
-	return
+	return s3.NewStorage(sess, bucket, prefix), prefix, nil
 }
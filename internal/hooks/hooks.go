@@ -0,0 +1,134 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/sputnik-systems/backups-operator/internal/tracing"
+)
+
+// maxOutputSize is max size of hook output kept in result
+const maxOutputSize = 1024
+
+// Exec runs command in pod container through pods/exec subresource and returns its output
+func Exec(ctx context.Context, config *rest.Config, ns, pod, container string, command []string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "hooks.Exec", append(tracing.Object(pod, ns), attribute.String("hook.container", container))...)
+	defer func() { tracing.End(span, err) }()
+
+	cs, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to create client: %w", err)
+	}
+
+	req := cs.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(pod).
+		Namespace(ns).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(config, http.MethodPost, req.URL())
+	if err != nil {
+		return "", fmt.Errorf("failed to create executor: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	// stream does not support context, so it is left to be closed by api server on timeout
+	done := make(chan error, 1)
+	go func() {
+		done <- executor.Stream(remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr})
+	}()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case err := <-done:
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", err, truncate(stderr.String()))
+		}
+	}
+
+	return truncate(stdout.String()), nil
+}
+
+// HTTP sends request and returns response body, non 2xx response status is error
+func HTTP(ctx context.Context, method, url string, headers map[string]string, body string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "hooks.HTTP", attribute.String("http.url", url))
+	defer func() { tracing.End(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	return do(req)
+}
+
+// SQL executes query through clickhouse http interface and returns its result
+func SQL(ctx context.Context, address, username, password, query string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "hooks.SQL", attribute.String("db.statement", query))
+	defer func() { tracing.End(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, strings.NewReader(query))
+	if err != nil {
+		return "", err
+	}
+
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	return do(req)
+}
+
+func do(req *http.Request) (string, error) {
+	c := &http.Client{Transport: tracing.Transport(nil)}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxOutputSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
+
+func truncate(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > maxOutputSize {
+		return s[:maxOutputSize]
+	}
+
+	return s
+}
@@ -0,0 +1,76 @@
+package hooks_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sputnik-systems/backups-operator/internal/hooks"
+)
+
+func TestHTTP(t *testing.T) {
+	var method, token, body string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		token = r.Header.Get("Authorization")
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+		fmt.Fprintln(w, "drained")
+	}))
+	defer s.Close()
+
+	out, err := hooks.HTTP(context.Background(), http.MethodPut, s.URL, map[string]string{"Authorization": "Bearer token"}, `{"drain":true}`)
+	if err != nil {
+		t.Fatalf("failed to send request: %s", err)
+	}
+
+	if out != "drained" || method != http.MethodPut || token != "Bearer token" || body != `{"drain":true}` {
+		t.Fatalf("unexpected request %s %q %q, output %q", method, token, body, out)
+	}
+
+	s.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, "busy")
+	})
+
+	if _, err := hooks.HTTP(context.Background(), http.MethodPost, s.URL, nil, ""); err == nil || !strings.Contains(err.Error(), "busy") {
+		t.Fatalf("expected status code error, got %v", err)
+	}
+}
+
+func TestHTTPTimeout(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := hooks.HTTP(ctx, http.MethodPost, s.URL, nil, ""); err == nil {
+		t.Fatal("expected timeout error")
+	}
+}
+
+func TestSQL(t *testing.T) {
+	var query, username, password string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		query = string(data)
+		username, password, _ = r.BasicAuth()
+	}))
+	defer s.Close()
+
+	if _, err := hooks.SQL(context.Background(), s.URL, "backup", "secret", "SYSTEM FLUSH LOGS"); err != nil {
+		t.Fatalf("failed to execute query: %s", err)
+	}
+
+	if query != "SYSTEM FLUSH LOGS" || username != "backup" || password != "secret" {
+		t.Fatalf("unexpected request %q as %s:%s", query, username, password)
+	}
+}
@@ -0,0 +1,673 @@
+package inventory
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+)
+
+const (
+	EngineClickHouse = "clickhouse"
+	EngineDgraph     = "dgraph"
+)
+
+// bearerPrefix is authorization header prefix of bearer token
+const bearerPrefix = "Bearer "
+
+// phaseCompleted is successfully finished backup phase
+const phaseCompleted = "Completed"
+
+// maxBodySize is max size of request body
+const maxBodySize = 1 << 20
+
+// apiGroup is backup objects api group, which is checked by SubjectAccessReview
+const apiGroup = "backups.sputnik.systems"
+
+//go:embed ui
+var ui embed.FS
+
+// Schedule is backup schedule with computed fields
+type Schedule struct {
+	Engine             string     `json:"engine"`
+	Namespace          string     `json:"namespace"`
+	Name               string     `json:"name"`
+	Schedule           string     `json:"schedule"`
+	TimeZone           string     `json:"timeZone,omitempty"`
+	Retention          string     `json:"retention,omitempty"`
+	Suspend            bool       `json:"suspend"`
+	Fresh              *bool      `json:"fresh,omitempty"`
+	LastScheduleTime   *time.Time `json:"lastScheduleTime,omitempty"`
+	LastSuccessfulTime *time.Time `json:"lastSuccessfulTime,omitempty"`
+	LastSuccessAge     string     `json:"lastSuccessAge,omitempty"`
+	NextScheduleTime   *time.Time `json:"nextScheduleTime,omitempty"`
+	Active             int        `json:"active"`
+	Retained           int        `json:"retained"`
+}
+
+// Backup is backup object with computed fields
+type Backup struct {
+	Engine          string     `json:"engine"`
+	Namespace       string     `json:"namespace"`
+	Name            string     `json:"name"`
+	Schedule        string     `json:"schedule,omitempty"`
+	Location        string     `json:"location"`
+	Phase           string     `json:"phase"`
+	Error           string     `json:"error,omitempty"`
+	Size            int64      `json:"size,omitempty"`
+	Imported        bool       `json:"imported,omitempty"`
+	CreationTime    time.Time  `json:"creationTime"`
+	CompletionTime  *time.Time `json:"completionTime,omitempty"`
+	Age             string     `json:"age"`
+	DurationSeconds float64    `json:"durationSeconds,omitempty"`
+}
+
+// Location is remote storage location with backups summary.
+// Dgraph location is export destination, clickhouse location is clickhouse-backup api address.
+type Location struct {
+	Engine         string     `json:"engine"`
+	Namespace      string     `json:"namespace"`
+	Location       string     `json:"location"`
+	Backups        int        `json:"backups"`
+	Completed      int        `json:"completed"`
+	Size           int64      `json:"size,omitempty"`
+	LastBackupTime *time.Time `json:"lastBackupTime,omitempty"`
+}
+
+// Restore is restore object with computed fields
+type Restore struct {
+	Engine          string     `json:"engine"`
+	Namespace       string     `json:"namespace"`
+	Name            string     `json:"name"`
+	Backup          string     `json:"backup"`
+	BackupNamespace string     `json:"backupNamespace"`
+	Target          string     `json:"target"`
+	Phase           string     `json:"phase"`
+	Error           string     `json:"error,omitempty"`
+	CreationTime    time.Time  `json:"creationTime"`
+	CompletionTime  *time.Time `json:"completionTime,omitempty"`
+	Age             string     `json:"age"`
+}
+
+// Trigger is triggered schedule response
+type Trigger struct {
+	Engine    string `json:"engine"`
+	Namespace string `json:"namespace"`
+	Schedule  string `json:"schedule"`
+	Trigger   string `json:"trigger"`
+}
+
+// Handler serves backups inventory api under /api/ and web ui under /ui/.
+// Api requests are authenticated by bearer token with TokenReview and authorized with SubjectAccessReview,
+// so user RBAC permissions are applied. Objects are read and written with operator client.
+type Handler struct {
+	client client.Client
+	log    logr.Logger
+
+	// reviewToken returns user authenticated by given token, it is replaced in tests
+	reviewToken func(ctx context.Context, token string) (*authenticationv1.UserInfo, error)
+
+	// reviewAccess checks if user is allowed to access given resource, it is replaced in tests
+	reviewAccess func(ctx context.Context, user *authenticationv1.UserInfo, attrs *authorizationv1.ResourceAttributes) (bool, error)
+}
+
+// NewHandler returns inventory api and ui handler
+func NewHandler(c client.Client, l logr.Logger) *Handler {
+	h := &Handler{client: c, log: l}
+	h.reviewToken = h.tokenReview
+	h.reviewAccess = h.subjectAccessReview
+
+	return h
+}
+
+// SetTokenReviewFunc overrides request tokens authentication
+func (h *Handler) SetTokenReviewFunc(f func(ctx context.Context, token string) (*authenticationv1.UserInfo, error)) {
+	h.reviewToken = f
+}
+
+// SetAccessReviewFunc overrides request users authorization
+func (h *Handler) SetAccessReviewFunc(f func(ctx context.Context, user *authenticationv1.UserInfo, attrs *authorizationv1.ResourceAttributes) (bool, error)) {
+	h.reviewAccess = f
+}
+
+// Register registers api and ui handlers with given function, e.g. manager AddMetricsExtraHandler
+func (h *Handler) Register(add func(path string, handler http.Handler) error) error {
+	static, err := fs.Sub(ui, "ui")
+	if err != nil {
+		return err
+	}
+
+	if err := add("/api/", h); err != nil {
+		return err
+	}
+
+	return add("/ui/", http.StripPrefix("/ui/", http.FileServer(http.FS(static))))
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1"), "/")
+
+	switch {
+	case r.Method == http.MethodGet && path == "schedules":
+		h.serveList(w, r, "backupschedules", h.listSchedules)
+	case r.Method == http.MethodGet && path == "backups":
+		h.serveList(w, r, "backups", h.listBackups)
+	case r.Method == http.MethodGet && path == "locations":
+		h.serveList(w, r, "backups", h.listLocations)
+	case r.Method == http.MethodGet && path == "restores":
+		h.serveList(w, r, "restores", h.listRestores)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "restores"):
+		h.serveRestore(w, r, strings.Split(path, "/"))
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/trigger"):
+		h.serveTrigger(w, r, strings.Split(path, "/"))
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+// listFunc returns list of objects filtered by request query
+type listFunc func(ctx context.Context, q query) (interface{}, error)
+
+// query is list request filters
+type query struct {
+	namespace string
+	engine    string
+	schedule  string
+}
+
+// serveList serves objects list, user must be allowed to list engine resources with given suffix,
+// e.g. clickhousebackups and dgraphbackups for "backups" suffix.
+func (h *Handler) serveList(w http.ResponseWriter, r *http.Request, suffix string, f listFunc) {
+	q := query{
+		namespace: r.URL.Query().Get("namespace"),
+		engine:    r.URL.Query().Get("engine"),
+		schedule:  r.URL.Query().Get("schedule"),
+	}
+
+	switch q.engine {
+	case "", EngineClickHouse, EngineDgraph:
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown engine %q", q.engine))
+		return
+	}
+
+	user, ok := h.getUser(w, r)
+	if !ok {
+		return
+	}
+
+	for _, engine := range []string{EngineClickHouse, EngineDgraph} {
+		if q.engine != "" && q.engine != engine {
+			continue
+		}
+
+		attrs := &authorizationv1.ResourceAttributes{Namespace: q.namespace, Verb: "list", Group: apiGroup, Resource: engine + suffix}
+		if !h.authorize(w, r, user, attrs) {
+			return
+		}
+	}
+
+	out, err := f(r.Context(), q)
+	if err != nil {
+		h.log.V(1).Info("failed to list objects", "path", r.URL.Path, "error", err.Error())
+		writeError(w, getStatusCode(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+// serveTrigger handles POST /api/v1/namespaces/{namespace}/{engine}backupschedules/{name}/trigger
+func (h *Handler) serveTrigger(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) != 5 || parts[0] != "namespaces" {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+
+	ns, resource, name := parts[1], parts[2], parts[3]
+
+	var obj client.Object
+	var engine string
+	switch resource {
+	case "clickhousebackupschedules":
+		obj, engine = &backupsv1alpha1.ClickHouseBackupSchedule{}, EngineClickHouse
+	case "dgraphbackupschedules":
+		obj, engine = &backupsv1alpha1.DgraphBackupSchedule{}, EngineDgraph
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown resource %q", resource))
+		return
+	}
+
+	user, ok := h.getUser(w, r)
+	if !ok {
+		return
+	}
+
+	attrs := &authorizationv1.ResourceAttributes{Namespace: ns, Verb: "patch", Group: apiGroup, Resource: resource, Name: name}
+	if !h.authorize(w, r, user, attrs) {
+		return
+	}
+
+	obj.SetNamespace(ns)
+	obj.SetName(name)
+
+	trigger := strconv.FormatInt(time.Now().Unix(), 10)
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, backupsv1alpha1.TriggerAnnotation, trigger)
+	if err := h.client.Patch(r.Context(), obj, client.RawPatch(types.MergePatchType, []byte(patch))); err != nil {
+		writeError(w, getStatusCode(err), fmt.Errorf("failed to trigger schedule: %w", err))
+		return
+	}
+
+	h.log.V(1).Info("schedule triggered by api", "user", user.Username, "schedule", name, "namespace", ns, "engine", engine)
+
+	writeJSON(w, http.StatusAccepted, Trigger{Engine: engine, Namespace: ns, Schedule: name, Trigger: trigger})
+}
+
+// serveRestore handles POST /api/v1/namespaces/{namespace}/{engine}restores with restore object in request body
+func (h *Handler) serveRestore(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) != 3 || parts[0] != "namespaces" {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+
+	ns, resource := parts[1], parts[2]
+
+	var obj client.Object
+	var engine string
+	switch resource {
+	case "clickhouserestores":
+		obj, engine = &backupsv1alpha1.ClickHouseRestore{}, EngineClickHouse
+	case "dgraphrestores":
+		obj, engine = &backupsv1alpha1.DgraphRestore{}, EngineDgraph
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown resource %q", resource))
+		return
+	}
+
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(obj); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("failed to decode restore object: %w", err))
+		return
+	}
+
+	user, ok := h.getUser(w, r)
+	if !ok {
+		return
+	}
+
+	attrs := &authorizationv1.ResourceAttributes{Namespace: ns, Verb: "create", Group: apiGroup, Resource: resource}
+	if !h.authorize(w, r, user, attrs) {
+		return
+	}
+
+	obj.SetNamespace(ns)
+	obj.SetResourceVersion("")
+
+	if err := h.client.Create(r.Context(), obj); err != nil {
+		writeError(w, getStatusCode(err), fmt.Errorf("failed to create restore: %w", err))
+		return
+	}
+
+	h.log.V(1).Info("restore created by api", "user", user.Username, "restore", obj.GetName(), "namespace", ns, "engine", engine)
+
+	writeJSON(w, http.StatusCreated, obj)
+}
+
+// getUser returns user authenticated by request bearer token.
+// Error response is written, if user is not returned.
+func (h *Handler) getUser(w http.ResponseWriter, r *http.Request) (*authenticationv1.UserInfo, bool) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, bearerPrefix) {
+		writeError(w, http.StatusUnauthorized, errors.New("request bearer token is not set"))
+		return nil, false
+	}
+
+	info, err := h.reviewToken(r.Context(), strings.TrimSpace(strings.TrimPrefix(header, bearerPrefix)))
+	if err != nil {
+		h.log.Error(err, "failed to review request token")
+		writeError(w, http.StatusInternalServerError, errors.New("failed to review request token"))
+		return nil, false
+	}
+
+	if info == nil {
+		writeError(w, http.StatusUnauthorized, errors.New("request bearer token is not valid"))
+		return nil, false
+	}
+
+	return info, true
+}
+
+// authorize checks if user is allowed to access given resource.
+// Error response is written, if access is not allowed.
+func (h *Handler) authorize(w http.ResponseWriter, r *http.Request, user *authenticationv1.UserInfo, attrs *authorizationv1.ResourceAttributes) bool {
+	allowed, err := h.reviewAccess(r.Context(), user, attrs)
+	if err != nil {
+		h.log.Error(err, "failed to review user access", "user", user.Username)
+		writeError(w, http.StatusInternalServerError, errors.New("failed to review user access"))
+		return false
+	}
+
+	if !allowed {
+		scope := "cluster scope"
+		if attrs.Namespace != "" {
+			scope = fmt.Sprintf("namespace %q", attrs.Namespace)
+		}
+
+		writeError(w, http.StatusForbidden, fmt.Errorf("user %q cannot %s %s in %s", user.Username, attrs.Verb, attrs.Resource, scope))
+		return false
+	}
+
+	return true
+}
+
+// tokenReview returns user authenticated by given token, nil if token is not valid
+func (h *Handler) tokenReview(ctx context.Context, token string) (*authenticationv1.UserInfo, error) {
+	tr := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	if err := h.client.Create(ctx, tr); err != nil {
+		return nil, fmt.Errorf("failed to create token review: %w", err)
+	}
+
+	if !tr.Status.Authenticated || tr.Status.User.Username == "" {
+		return nil, nil
+	}
+
+	return &tr.Status.User, nil
+}
+
+// subjectAccessReview checks if user is allowed to access given resource
+func (h *Handler) subjectAccessReview(ctx context.Context, user *authenticationv1.UserInfo, attrs *authorizationv1.ResourceAttributes) (bool, error) {
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: attrs,
+			User:               user.Username,
+			Groups:             user.Groups,
+			UID:                user.UID,
+			Extra:              extra,
+		},
+	}
+	if err := h.client.Create(ctx, sar); err != nil {
+		return false, fmt.Errorf("failed to create subject access review: %w", err)
+	}
+
+	return sar.Status.Allowed, nil
+}
+
+func (h *Handler) listSchedules(ctx context.Context, q query) (interface{}, error) {
+	out := make([]Schedule, 0)
+
+	if q.engine == "" || q.engine == EngineClickHouse {
+		sl := &backupsv1alpha1.ClickHouseBackupScheduleList{}
+		if err := h.client.List(ctx, sl, client.InNamespace(q.namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list clickhouse backup schedules: %w", err)
+		}
+
+		for _, bs := range sl.Items {
+			out = append(out, newSchedule(EngineClickHouse, &bs.ObjectMeta, bs.Spec.Schedule, bs.Spec.TimeZone, bs.Spec.Retention, bs.Spec.Suspend, &bs.Status.BackupScheduleHistory, bs.Status.Conditions))
+		}
+	}
+
+	if q.engine == "" || q.engine == EngineDgraph {
+		sl := &backupsv1alpha1.DgraphBackupScheduleList{}
+		if err := h.client.List(ctx, sl, client.InNamespace(q.namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list dgraph backup schedules: %w", err)
+		}
+
+		for _, bs := range sl.Items {
+			out = append(out, newSchedule(EngineDgraph, &bs.ObjectMeta, bs.Spec.Schedule, bs.Spec.TimeZone, bs.Spec.Retention, bs.Spec.Suspend, &bs.Status.BackupScheduleHistory, bs.Status.Conditions))
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+
+		return out[i].Name < out[j].Name
+	})
+
+	return out, nil
+}
+
+func (h *Handler) listBackups(ctx context.Context, q query) (interface{}, error) {
+	return h.getBackups(ctx, q)
+}
+
+func (h *Handler) getBackups(ctx context.Context, q query) ([]Backup, error) {
+	opts := []client.ListOption{client.InNamespace(q.namespace)}
+	if q.schedule != "" {
+		opts = append(opts, client.MatchingLabels{backupsv1alpha1.ScheduleNameLabel: q.schedule})
+	}
+
+	out := make([]Backup, 0)
+
+	if q.engine == "" || q.engine == EngineClickHouse {
+		bl := &backupsv1alpha1.ClickHouseBackupList{}
+		if err := h.client.List(ctx, bl, opts...); err != nil {
+			return nil, fmt.Errorf("failed to list clickhouse backups: %w", err)
+		}
+
+		for i := range bl.Items {
+			b := &bl.Items[i]
+			out = append(out, newBackup(EngineClickHouse, b, b.Spec.ApiAddress, b.Status.Phase, b.Status.Error, b.Status.Size, b.Status.CompletionTime))
+		}
+	}
+
+	if q.engine == "" || q.engine == EngineDgraph {
+		bl := &backupsv1alpha1.DgraphBackupList{}
+		if err := h.client.List(ctx, bl, opts...); err != nil {
+			return nil, fmt.Errorf("failed to list dgraph backups: %w", err)
+		}
+
+		for i := range bl.Items {
+			b := &bl.Items[i]
+			out = append(out, newBackup(EngineDgraph, b, b.Spec.Destination, b.Status.Phase, b.Status.Error, b.Status.Size, b.Status.CompletionTime))
+		}
+	}
+
+	// newest backups first
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].CreationTime.After(out[j].CreationTime)
+	})
+
+	return out, nil
+}
+
+func (h *Handler) listLocations(ctx context.Context, q query) (interface{}, error) {
+	backups, err := h.getBackups(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make(map[string]*Location)
+	out := make([]*Location, 0)
+
+	for i := range backups {
+		b := &backups[i]
+
+		key := strings.Join([]string{b.Engine, b.Namespace, b.Location}, "/")
+		l, ok := locations[key]
+		if !ok {
+			l = &Location{Engine: b.Engine, Namespace: b.Namespace, Location: b.Location}
+			locations[key] = l
+			out = append(out, l)
+		}
+
+		l.Backups++
+		l.Size += b.Size
+
+		if b.Phase == phaseCompleted {
+			l.Completed++
+
+			if l.LastBackupTime == nil || b.CreationTime.After(*l.LastBackupTime) {
+				t := b.CreationTime
+				l.LastBackupTime = &t
+			}
+		}
+	}
+
+	return out, nil
+}
+
+func (h *Handler) listRestores(ctx context.Context, q query) (interface{}, error) {
+	out := make([]Restore, 0)
+
+	if q.engine == "" || q.engine == EngineClickHouse {
+		rl := &backupsv1alpha1.ClickHouseRestoreList{}
+		if err := h.client.List(ctx, rl, client.InNamespace(q.namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list clickhouse restores: %w", err)
+		}
+
+		for i := range rl.Items {
+			r := &rl.Items[i]
+
+			target := r.Status.Api.Address
+			if r.Spec.Target != nil {
+				target = r.Spec.Target.ApiAddress
+			}
+
+			out = append(out, newRestore(EngineClickHouse, r, &r.Spec.Backup, target, r.Status.Phase, r.Status.Error, r.Status.CompletionTime))
+		}
+	}
+
+	if q.engine == "" || q.engine == EngineDgraph {
+		rl := &backupsv1alpha1.DgraphRestoreList{}
+		if err := h.client.List(ctx, rl, client.InNamespace(q.namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list dgraph restores: %w", err)
+		}
+
+		for i := range rl.Items {
+			r := &rl.Items[i]
+			out = append(out, newRestore(EngineDgraph, r, &r.Spec.Backup, r.Spec.AlphaAddress, r.Status.Phase, r.Status.Error, r.Status.CompletionTime))
+		}
+	}
+
+	// newest restores first
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].CreationTime.After(out[j].CreationTime)
+	})
+
+	return out, nil
+}
+
+func newSchedule(engine string, om *metav1.ObjectMeta, schedule, timeZone, retention string, suspend bool, history *backupsv1alpha1.BackupScheduleHistory, conditions []metav1.Condition) Schedule {
+	s := Schedule{
+		Engine:             engine,
+		Namespace:          om.Namespace,
+		Name:               om.Name,
+		Schedule:           schedule,
+		TimeZone:           timeZone,
+		Retention:          retention,
+		Suspend:            suspend,
+		LastScheduleTime:   getTime(history.LastScheduleTime),
+		LastSuccessfulTime: getTime(history.LastSuccessfulTime),
+		NextScheduleTime:   getTime(history.NextScheduleTime),
+		Active:             history.Active,
+		Retained:           history.Retained,
+	}
+
+	if c := meta.FindStatusCondition(conditions, backupsv1alpha1.ConditionFresh); c != nil {
+		fresh := c.Status == metav1.ConditionTrue
+		s.Fresh = &fresh
+	}
+
+	if s.LastSuccessfulTime != nil {
+		s.LastSuccessAge = duration.HumanDuration(time.Since(*s.LastSuccessfulTime))
+	}
+
+	return s
+}
+
+func newBackup(engine string, obj client.Object, location, phase, message string, size int64, completion *metav1.Time) Backup {
+	b := Backup{
+		Engine:         engine,
+		Namespace:      obj.GetNamespace(),
+		Name:           obj.GetName(),
+		Schedule:       obj.GetLabels()[backupsv1alpha1.ScheduleNameLabel],
+		Location:       location,
+		Phase:          phase,
+		Error:          message,
+		Size:           size,
+		Imported:       backupsv1alpha1.IsImported(obj),
+		CreationTime:   backupsv1alpha1.GetCreationTime(obj),
+		CompletionTime: getTime(completion),
+	}
+
+	b.Age = duration.HumanDuration(time.Since(b.CreationTime))
+	if b.CompletionTime != nil {
+		b.DurationSeconds = b.CompletionTime.Sub(b.CreationTime).Seconds()
+	}
+
+	return b
+}
+
+func newRestore(engine string, obj client.Object, backup *backupsv1alpha1.BackupReference, target, phase, message string, completion *metav1.Time) Restore {
+	r := Restore{
+		Engine:          engine,
+		Namespace:       obj.GetNamespace(),
+		Name:            obj.GetName(),
+		Backup:          backup.Name,
+		BackupNamespace: backup.GetNamespace(obj.GetNamespace()),
+		Target:          target,
+		Phase:           phase,
+		Error:           message,
+		CreationTime:    obj.GetCreationTimestamp().Time,
+		CompletionTime:  getTime(completion),
+	}
+
+	r.Age = duration.HumanDuration(time.Since(r.CreationTime))
+
+	return r
+}
+
+func getTime(t *metav1.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	return &t.Time
+}
+
+func getStatusCode(err error) int {
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		if code := int(status.Status().Code); code != 0 {
+			return code
+		}
+	}
+
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
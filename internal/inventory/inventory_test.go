@@ -0,0 +1,302 @@
+package inventory_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
+	"github.com/sputnik-systems/backups-operator/internal/inventory"
+)
+
+func newHandler(t *testing.T) (*inventory.Handler, client.Client) {
+	scheme := runtime.NewScheme()
+	if err := backupsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	created := metav1.NewTime(time.Now().Add(-time.Hour))
+	labels := map[string]string{backupsv1alpha1.ScheduleNameLabel: "daily"}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&backupsv1alpha1.ClickHouseBackupSchedule{
+			ObjectMeta: metav1.ObjectMeta{Name: "daily", Namespace: "prod"},
+			Spec:       backupsv1alpha1.ClickHouseBackupScheduleSpec{Schedule: "0 3 * * *"},
+			Status: backupsv1alpha1.ClickHouseBackupScheduleStatus{
+				BackupScheduleHistory: backupsv1alpha1.BackupScheduleHistory{LastSuccessfulTime: &created},
+				Conditions:            []metav1.Condition{{Type: backupsv1alpha1.ConditionFresh, Status: metav1.ConditionTrue}},
+			},
+		},
+		&backupsv1alpha1.ClickHouseBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "daily-1", Namespace: "prod", Labels: labels, CreationTimestamp: created},
+			Spec:       backupsv1alpha1.ClickHouseBackupSpec{ApiAddress: "http://clickhouse:7171"},
+			Status:     backupsv1alpha1.ClickHouseBackupStatus{Phase: "Completed", Size: 1024},
+		},
+		&backupsv1alpha1.ClickHouseBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "daily-2", Namespace: "prod", Labels: labels, CreationTimestamp: metav1.Now()},
+			Spec:       backupsv1alpha1.ClickHouseBackupSpec{ApiAddress: "http://clickhouse:7171"},
+			Status:     backupsv1alpha1.ClickHouseBackupStatus{Phase: "Failed"},
+		},
+		&backupsv1alpha1.DgraphBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: "staging", CreationTimestamp: created},
+			Spec:       backupsv1alpha1.DgraphBackupSpec{Destination: "s3://bucket/dgraph"},
+			Status:     backupsv1alpha1.DgraphBackupStatus{Phase: "Completed"},
+		},
+	).Build()
+
+	h := inventory.NewHandler(c, logr.Discard())
+	h.SetTokenReviewFunc(func(ctx context.Context, token string) (*authenticationv1.UserInfo, error) {
+		if token != "alice-token" {
+			return nil, nil
+		}
+
+		return &authenticationv1.UserInfo{Username: "alice", Groups: []string{"dev", "ops"}}, nil
+	})
+	h.SetAccessReviewFunc(func(ctx context.Context, user *authenticationv1.UserInfo, attrs *authorizationv1.ResourceAttributes) (bool, error) {
+		return true, nil
+	})
+
+	return h, c
+}
+
+func newRequest(method, url, token string, body string) *http.Request {
+	r := httptest.NewRequest(method, url, strings.NewReader(body))
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return r
+}
+
+func get(t *testing.T, h http.Handler, url string, v interface{}) {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest(http.MethodGet, url, "alice-token", ""))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected %s response status %d: %s", url, w.Code, w.Body)
+	}
+
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSchedules(t *testing.T) {
+	h, _ := newHandler(t)
+
+	var schedules []inventory.Schedule
+	get(t, h, "/api/v1/schedules", &schedules)
+
+	if len(schedules) != 1 {
+		t.Fatalf("unexpected schedules: %+v", schedules)
+	}
+
+	s := schedules[0]
+	if s.Engine != inventory.EngineClickHouse || s.Fresh == nil || !*s.Fresh || s.LastSuccessAge == "" {
+		t.Errorf("unexpected schedule: %+v", s)
+	}
+}
+
+func TestUnauthenticatedList(t *testing.T) {
+	h, _ := newHandler(t)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest(http.MethodGet, "/api/v1/backups", "", ""))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected unauthorized list without token, got %d", w.Code)
+	}
+}
+
+func TestBackups(t *testing.T) {
+	h, _ := newHandler(t)
+
+	var backups []inventory.Backup
+	get(t, h, "/api/v1/backups", &backups)
+
+	if len(backups) != 3 || backups[0].Name != "daily-2" {
+		t.Fatalf("expected newest backups first, got %+v", backups)
+	}
+
+	get(t, h, "/api/v1/backups?namespace=prod&schedule=daily", &backups)
+	if len(backups) != 2 || backups[1].Size != 1024 || backups[1].Schedule != "daily" {
+		t.Errorf("unexpected filtered backups: %+v", backups)
+	}
+
+	get(t, h, "/api/v1/backups?engine=dgraph", &backups)
+	if len(backups) != 1 || backups[0].Location != "s3://bucket/dgraph" {
+		t.Errorf("unexpected dgraph backups: %+v", backups)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest(http.MethodGet, "/api/v1/backups?engine=mysql", "alice-token", ""))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected bad request for unknown engine, got %d", w.Code)
+	}
+}
+
+func TestLocations(t *testing.T) {
+	h, _ := newHandler(t)
+
+	var locations []inventory.Location
+	get(t, h, "/api/v1/locations?engine=clickhouse", &locations)
+
+	if len(locations) != 1 {
+		t.Fatalf("unexpected locations: %+v", locations)
+	}
+
+	l := locations[0]
+	if l.Backups != 2 || l.Completed != 1 || l.Size != 1024 || l.LastBackupTime == nil {
+		t.Errorf("unexpected location: %+v", l)
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	h, c := newHandler(t)
+
+	var user *authenticationv1.UserInfo
+	var reviewed *authorizationv1.ResourceAttributes
+	h.SetAccessReviewFunc(func(ctx context.Context, u *authenticationv1.UserInfo, attrs *authorizationv1.ResourceAttributes) (bool, error) {
+		user, reviewed = u, attrs
+		return true, nil
+	})
+
+	url := "/api/v1/namespaces/prod/clickhousebackupschedules/daily/trigger"
+
+	for _, token := range []string{"", "forged-token"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newRequest(http.MethodPost, url, token, ""))
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected unauthorized with token %q, got %d", token, w.Code)
+		}
+	}
+
+	r := newRequest(http.MethodPost, url, "alice-token", "")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("unexpected response status %d: %s", w.Code, w.Body)
+	}
+
+	if user.Username != "alice" || len(user.Groups) != 2 {
+		t.Errorf("unexpected reviewed user %+v", user)
+	}
+
+	expected := authorizationv1.ResourceAttributes{Namespace: "prod", Verb: "patch", Group: "backups.sputnik.systems", Resource: "clickhousebackupschedules", Name: "daily"}
+	if *reviewed != expected {
+		t.Errorf("unexpected reviewed attributes %+v", reviewed)
+	}
+
+	bs := &backupsv1alpha1.ClickHouseBackupSchedule{}
+	if err := c.Get(r.Context(), types.NamespacedName{Namespace: "prod", Name: "daily"}, bs); err != nil {
+		t.Fatal(err)
+	}
+
+	if bs.Annotations[backupsv1alpha1.TriggerAnnotation] == "" {
+		t.Error("trigger annotation is not set")
+	}
+
+	r = newRequest(http.MethodPost, "/api/v1/namespaces/prod/clickhousebackupschedules/missing/trigger", "alice-token", "")
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected not found for missing schedule, got %d", w.Code)
+	}
+}
+
+func TestRestores(t *testing.T) {
+	h, _ := newHandler(t)
+
+	body := `{"metadata":{"name":"refresh"},"spec":{"backup":{"name":"daily-1","namespace":"prod"},"target":{"apiAddress":"http://clickhouse:7171"}}}`
+	r := newRequest(http.MethodPost, "/api/v1/namespaces/staging/clickhouserestores", "alice-token", body)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("unexpected response status %d: %s", w.Code, w.Body)
+	}
+
+	var restores []inventory.Restore
+	get(t, h, "/api/v1/restores?namespace=staging", &restores)
+
+	if len(restores) != 1 {
+		t.Fatalf("unexpected restores: %+v", restores)
+	}
+
+	if r := restores[0]; r.Name != "refresh" || r.BackupNamespace != "prod" || r.Target != "http://clickhouse:7171" {
+		t.Errorf("unexpected restore: %+v", r)
+	}
+}
+
+func TestForbidden(t *testing.T) {
+	h, c := newHandler(t)
+
+	var reviewed []authorizationv1.ResourceAttributes
+	h.SetAccessReviewFunc(func(ctx context.Context, u *authenticationv1.UserInfo, attrs *authorizationv1.ResourceAttributes) (bool, error) {
+		reviewed = append(reviewed, *attrs)
+
+		// alice is allowed to list clickhouse backups in prod namespace only
+		return attrs.Verb == "list" && attrs.Resource == "clickhousebackups" && attrs.Namespace == "prod", nil
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+		code   int
+	}{
+		{name: "allowed list", method: http.MethodGet, url: "/api/v1/backups?namespace=prod&engine=clickhouse", code: http.StatusOK},
+		{name: "cluster wide list", method: http.MethodGet, url: "/api/v1/backups?engine=clickhouse", code: http.StatusForbidden},
+		{name: "all engines list", method: http.MethodGet, url: "/api/v1/backups?namespace=prod", code: http.StatusForbidden},
+		{name: "schedules list", method: http.MethodGet, url: "/api/v1/schedules?namespace=prod&engine=clickhouse", code: http.StatusForbidden},
+		{name: "trigger", method: http.MethodPost, url: "/api/v1/namespaces/prod/clickhousebackupschedules/daily/trigger", code: http.StatusForbidden},
+		{name: "restore", method: http.MethodPost, url: "/api/v1/namespaces/prod/dgraphrestores", body: `{"metadata":{"name":"refresh"}}`, code: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, newRequest(tt.method, tt.url, "alice-token", tt.body))
+			if w.Code != tt.code {
+				t.Errorf("expected response status %d, got %d: %s", tt.code, w.Code, w.Body)
+			}
+		})
+	}
+
+	if len(reviewed) != 7 || reviewed[len(reviewed)-1].Verb != "create" || reviewed[len(reviewed)-1].Resource != "dgraphrestores" {
+		t.Errorf("unexpected reviewed attributes: %+v", reviewed)
+	}
+
+	bs := &backupsv1alpha1.ClickHouseBackupSchedule{}
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "prod", Name: "daily"}, bs); err != nil {
+		t.Fatal(err)
+	}
+
+	if bs.Annotations[backupsv1alpha1.TriggerAnnotation] != "" {
+		t.Error("forbidden trigger is applied")
+	}
+
+	rl := &backupsv1alpha1.DgraphRestoreList{}
+	if err := c.List(context.Background(), rl); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rl.Items) != 0 {
+		t.Errorf("forbidden restore is created: %+v", rl.Items)
+	}
+}
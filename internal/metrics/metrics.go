@@ -5,10 +5,28 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
+// Metric names are used by alerting rules and dashboard generator
+const (
+	BackupsName               = "backups_operator_backups"
+	ScheduledTaskFailuresName = "backups_operator_scheduled_task_failures_total"
+	MissingBackupsName        = "backups_operator_missing_backups"
+	OrphanedBackupsName       = "backups_operator_orphaned_backups"
+	LastSuccessTimestampName  = "backups_operator_last_success_timestamp_seconds"
+	OperationDurationName     = "backups_operator_operation_duration_seconds"
+	BackupSizeName            = "backups_operator_backup_size_bytes"
+	RetainedBackupsName       = "backups_operator_retained_backups"
+	RetentionDeletionsName    = "backups_operator_retention_deletions_total"
+	PhaseTransitionsName      = "backups_operator_phase_transitions_total"
+	ScheduleIntervalName      = "backups_operator_schedule_interval_seconds"
+	BackupPhaseTimestampName  = "backups_operator_backup_phase_timestamp_seconds"
+	StaleScheduleName         = "backups_operator_schedule_stale"
+	SkippedTicksName          = "backups_operator_skipped_ticks_total"
+)
+
 var (
 	BackupsByController = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "backups_operator_backups",
+			Name: BackupsName,
 			Help: "Number of created backups",
 		},
 		[]string{"name", "namespace", "controller", "status"},
@@ -16,10 +34,107 @@ var (
 
 	ScheduledTaskFailuresByControllerTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "backups_operator_scheduled_task_failures_total",
+			Name: ScheduledTaskFailuresName,
 			Help: "Count of backup schedule failures",
 		},
-		[]string{"name", "namespace", "controller", "type"},
+		[]string{"name", "namespace", "controller", "action"},
+	)
+
+	MissingBackupsByController = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MissingBackupsName,
+			Help: "Number of completed backup objects without data in remote storage",
+		},
+		[]string{"name", "namespace", "controller"},
+	)
+
+	OrphanedBackupsByController = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: OrphanedBackupsName,
+			Help: "Number of remote backups without backup objects",
+		},
+		[]string{"name", "namespace", "controller"},
+	)
+
+	LastSuccessTimestampBySchedule = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: LastSuccessTimestampName,
+			Help: "Unix timestamp of last successfully completed backup",
+		},
+		[]string{"engine", "schedule", "namespace"},
+	)
+
+	OperationDurationBySchedule = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    OperationDurationName,
+			Help:    "Duration of backup operations",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 16),
+		},
+		[]string{"engine", "schedule", "namespace", "operation"},
+	)
+
+	BackupSizeBySchedule = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: BackupSizeName,
+			Help: "Size of last completed backup in remote storage",
+		},
+		[]string{"engine", "schedule", "namespace"},
+	)
+
+	RetainedBackupsBySchedule = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: RetainedBackupsName,
+			Help: "Number of backup objects kept by last retention run",
+		},
+		[]string{"engine", "schedule", "namespace"},
+	)
+
+	RetentionDeletionsBySchedule = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: RetentionDeletionsName,
+			Help: "Count of backup objects deleted by retention",
+		},
+		[]string{"engine", "schedule", "namespace"},
+	)
+
+	ScheduleIntervalBySchedule = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: ScheduleIntervalName,
+			Help: "Interval between backup schedule executions",
+		},
+		[]string{"engine", "schedule", "namespace"},
+	)
+
+	BackupPhaseTimestampBySchedule = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: BackupPhaseTimestampName,
+			Help: "Unix timestamp of backup object current phase start",
+		},
+		[]string{"engine", "schedule", "namespace", "name", "phase"},
+	)
+
+	StaleBySchedule = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: StaleScheduleName,
+			Help: "Whether newest completed backup of schedule is older than max backup age",
+		},
+		[]string{"engine", "schedule", "namespace"},
+	)
+
+	PhaseTransitionsBySchedule = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: PhaseTransitionsName,
+			Help: "Count of backup objects phase transitions",
+		},
+		[]string{"engine", "schedule", "namespace", "phase"},
+	)
+
+	SkippedTicksBySchedule = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: SkippedTicksName,
+			Help: "Count of backup schedule ticks skipped because of suspension or blackout window",
+		},
+		[]string{"engine", "schedule", "namespace", "reason"},
 	)
 )
 
@@ -28,5 +143,17 @@ func init() {
 	metrics.Registry.MustRegister(
 		BackupsByController,
 		ScheduledTaskFailuresByControllerTotal,
+		MissingBackupsByController,
+		OrphanedBackupsByController,
+		LastSuccessTimestampBySchedule,
+		OperationDurationBySchedule,
+		BackupSizeBySchedule,
+		RetainedBackupsBySchedule,
+		RetentionDeletionsBySchedule,
+		PhaseTransitionsBySchedule,
+		ScheduleIntervalBySchedule,
+		BackupPhaseTimestampBySchedule,
+		StaleBySchedule,
+		SkippedTicksBySchedule,
 	)
 }
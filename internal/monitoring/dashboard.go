@@ -0,0 +1,225 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sputnik-systems/backups-operator/internal/metrics"
+)
+
+// dashboardSelector filters metrics by dashboard variables
+const dashboardSelector = `namespace=~"$namespace", schedule=~"$schedule"`
+
+type dashboard struct {
+	Title         string     `json:"title"`
+	UID           string     `json:"uid"`
+	Tags          []string   `json:"tags"`
+	Timezone      string     `json:"timezone"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Refresh       string     `json:"refresh"`
+	Time          timeRange  `json:"time"`
+	Templating    templating `json:"templating"`
+	Panels        []panel    `json:"panels"`
+}
+
+type timeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type templating struct {
+	List []variable `json:"list"`
+}
+
+type variable struct {
+	Name       string      `json:"name"`
+	Label      string      `json:"label"`
+	Type       string      `json:"type"`
+	Query      interface{} `json:"query"`
+	Datasource interface{} `json:"datasource,omitempty"`
+	Refresh    int         `json:"refresh,omitempty"`
+	Multi      bool        `json:"multi,omitempty"`
+	IncludeAll bool        `json:"includeAll,omitempty"`
+}
+
+type panel struct {
+	ID          int         `json:"id"`
+	Title       string      `json:"title"`
+	Type        string      `json:"type"`
+	Datasource  string      `json:"datasource"`
+	GridPos     gridPos     `json:"gridPos"`
+	FieldConfig fieldConfig `json:"fieldConfig"`
+	Targets     []target    `json:"targets"`
+}
+
+type gridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type fieldConfig struct {
+	Defaults fieldDefaults `json:"defaults"`
+}
+
+type fieldDefaults struct {
+	Unit string `json:"unit,omitempty"`
+}
+
+type target struct {
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat"`
+	RefID        string `json:"refId"`
+}
+
+// Dashboard returns grafana dashboard json for operator metrics
+func Dashboard() ([]byte, error) {
+	by := fmt.Sprintf("by (%s)", scheduleLabels)
+	legend := "{{namespace}}/{{schedule}} ({{engine}})"
+
+	panels := []struct {
+		title  string
+		kind   string
+		unit   string
+		expr   string
+		legend string
+	}{
+		{
+			title:  "Time since last success",
+			kind:   "stat",
+			unit:   "s",
+			expr:   fmt.Sprintf("time() - max %s (%s{%s})", by, metrics.LastSuccessTimestampName, dashboardSelector),
+			legend: legend,
+		},
+		{
+			title:  "Schedule interval",
+			kind:   "stat",
+			unit:   "s",
+			expr:   fmt.Sprintf("max %s (%s{%s})", by, metrics.ScheduleIntervalName, dashboardSelector),
+			legend: legend,
+		},
+		{
+			title:  "Operation duration (p95)",
+			kind:   "timeseries",
+			unit:   "s",
+			expr:   fmt.Sprintf("histogram_quantile(0.95, sum by (%s, operation, le) (rate(%s_bucket{%s}[1h])))", scheduleLabels, metrics.OperationDurationName, dashboardSelector),
+			legend: "{{namespace}}/{{schedule}} {{operation}}",
+		},
+		{
+			title:  "Backup size",
+			kind:   "timeseries",
+			unit:   "bytes",
+			expr:   fmt.Sprintf("max %s (%s{%s})", by, metrics.BackupSizeName, dashboardSelector),
+			legend: legend,
+		},
+		{
+			title:  "Retained backups",
+			kind:   "timeseries",
+			unit:   "short",
+			expr:   fmt.Sprintf("max %s (%s{%s})", by, metrics.RetainedBackupsName, dashboardSelector),
+			legend: legend,
+		},
+		{
+			title:  "Retention deletions",
+			kind:   "timeseries",
+			unit:   "short",
+			expr:   fmt.Sprintf("sum %s (increase(%s{%s}[1h]))", by, metrics.RetentionDeletionsName, dashboardSelector),
+			legend: legend,
+		},
+		{
+			title:  "Phase transitions",
+			kind:   "timeseries",
+			unit:   "short",
+			expr:   fmt.Sprintf("sum by (%s, phase) (increase(%s{%s}[1h]))", scheduleLabels, metrics.PhaseTransitionsName, dashboardSelector),
+			legend: "{{namespace}}/{{schedule}} {{phase}}",
+		},
+		{
+			title:  "Scheduled task failures",
+			kind:   "timeseries",
+			unit:   "short",
+			expr:   fmt.Sprintf("sum by (name, namespace, action) (increase(%s{namespace=~\"$namespace\", name=~\"$schedule\"}[1h]))", metrics.ScheduledTaskFailuresName),
+			legend: "{{namespace}}/{{name}} {{action}}",
+		},
+		{
+			title:  "Missing backups",
+			kind:   "timeseries",
+			unit:   "short",
+			expr:   fmt.Sprintf("max by (name, namespace) (%s{namespace=~\"$namespace\", name=~\"$schedule\"})", metrics.MissingBackupsName),
+			legend: "{{namespace}}/{{name}}",
+		},
+		{
+			title:  "Orphaned backups",
+			kind:   "timeseries",
+			unit:   "short",
+			expr:   fmt.Sprintf("max by (name, namespace) (%s{namespace=~\"$namespace\", name=~\"$schedule\"})", metrics.OrphanedBackupsName),
+			legend: "{{namespace}}/{{name}}",
+		},
+	}
+
+	d := dashboard{
+		Title:         "Backups Operator",
+		UID:           "backups-operator",
+		Tags:          []string{"backups-operator"},
+		Timezone:      "browser",
+		SchemaVersion: 30,
+		Refresh:       "1m",
+		Time:          timeRange{From: "now-7d", To: "now"},
+		Templating: templating{
+			List: []variable{
+				{
+					Name:  "datasource",
+					Label: "Data source",
+					Type:  "datasource",
+					Query: "prometheus",
+				},
+				{
+					Name:       "namespace",
+					Label:      "Namespace",
+					Type:       "query",
+					Query:      fmt.Sprintf("label_values(%s, namespace)", metrics.ScheduleIntervalName),
+					Datasource: "$datasource",
+					Refresh:    2,
+					Multi:      true,
+					IncludeAll: true,
+				},
+				{
+					Name:       "schedule",
+					Label:      "Schedule",
+					Type:       "query",
+					Query:      fmt.Sprintf("label_values(%s{namespace=~\"$namespace\"}, schedule)", metrics.ScheduleIntervalName),
+					Datasource: "$datasource",
+					Refresh:    2,
+					Multi:      true,
+					IncludeAll: true,
+				},
+			},
+		},
+	}
+
+	// panels are placed in two columns
+	for i, p := range panels {
+		d.Panels = append(d.Panels, panel{
+			ID:         i + 1,
+			Title:      p.title,
+			Type:       p.kind,
+			Datasource: "$datasource",
+			GridPos: gridPos{
+				H: 8,
+				W: 12,
+				X: (i % 2) * 12,
+				Y: (i / 2) * 8,
+			},
+			FieldConfig: fieldConfig{Defaults: fieldDefaults{Unit: p.unit}},
+			Targets: []target{
+				{
+					Expr:         p.expr,
+					LegendFormat: p.legend,
+					RefID:        "A",
+				},
+			},
+		})
+	}
+
+	return json.MarshalIndent(d, "", "  ")
+}
@@ -0,0 +1,203 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/sputnik-systems/backups-operator/internal/metrics"
+)
+
+// PrometheusRuleGVK is prometheus-operator PrometheusRule kind
+var PrometheusRuleGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "PrometheusRule",
+}
+
+// scheduleLabels is set of labels identifying backup schedule in metrics
+const scheduleLabels = "engine, schedule, namespace"
+
+// RuleGroup is prometheus alerting rules group
+type RuleGroup struct {
+	Name  string `json:"name"`
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is prometheus alerting rule
+type Rule struct {
+	Alert       string            `json:"alert"`
+	Expr        string            `json:"expr"`
+	For         string            `json:"for,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ScheduleInterval returns interval between two next executions of given cron schedule
+func ScheduleInterval(schedule string) (time.Duration, error) {
+	s, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse schedule: %w", err)
+	}
+
+	next := s.Next(time.Now())
+
+	return s.Next(next).Sub(next), nil
+}
+
+// Rules returns alerting rules for all backup schedules.
+// Thresholds are computed by prometheus from schedule interval metric.
+func Rules() []RuleGroup {
+	interval := fmt.Sprintf("max by (%s) (%s)", scheduleLabels, metrics.ScheduleIntervalName)
+
+	return []RuleGroup{
+		{
+			Name: "backups-operator",
+			Rules: []Rule{
+				noRecentSuccessRule(
+					fmt.Sprintf("time() - max by (%s) (%s) > 2 * %s", scheduleLabels, metrics.LastSuccessTimestampName, interval),
+				),
+				stuckRule(
+					fmt.Sprintf("time() - max by (%s, name, phase) (%s{phase=~\"Creating|Uploading\"}) > on (%s) group_left () 2 * %s",
+						scheduleLabels, metrics.BackupPhaseTimestampName, scheduleLabels, interval),
+				),
+				staleRule(fmt.Sprintf("max by (%s) (%s) > 0", scheduleLabels, metrics.StaleScheduleName)),
+				retentionRule(""),
+				failedRule(""),
+			},
+		},
+	}
+}
+
+// ScheduleRules returns alerting rules for given backup schedule with thresholds derived from its interval
+func ScheduleRules(engine, schedule, namespace string, interval time.Duration) []RuleGroup {
+	selector := fmt.Sprintf("engine=%q, schedule=%q, namespace=%q", engine, schedule, namespace)
+	threshold := int64((2 * interval).Seconds())
+
+	return []RuleGroup{
+		{
+			Name: fmt.Sprintf("backups-operator-%s-%s", engine, schedule),
+			Rules: []Rule{
+				noRecentSuccessRule(
+					fmt.Sprintf("time() - max by (%s) (%s{%s}) > %d", scheduleLabels, metrics.LastSuccessTimestampName, selector, threshold),
+				),
+				stuckRule(
+					fmt.Sprintf("time() - max by (%s, name, phase) (%s{%s, phase=~\"Creating|Uploading\"}) > %d",
+						scheduleLabels, metrics.BackupPhaseTimestampName, selector, threshold),
+				),
+				staleRule(fmt.Sprintf("max by (%s) (%s{%s}) > 0", scheduleLabels, metrics.StaleScheduleName, selector)),
+				retentionRule(selector),
+				failedRule(selector),
+			},
+		},
+	}
+}
+
+// PrometheusRule returns PrometheusRule object with given rule groups
+func PrometheusRule(name, namespace string, groups []RuleGroup) (*unstructured.Unstructured, error) {
+	data, err := json.Marshal(map[string]interface{}{"groups": groups})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rule groups: %w", err)
+	}
+
+	spec := make(map[string]interface{})
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rule groups: %w", err)
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(PrometheusRuleGVK)
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	obj.Object["spec"] = spec
+
+	return obj, nil
+}
+
+func noRecentSuccessRule(expr string) Rule {
+	return Rule{
+		Alert:  "BackupScheduleNoRecentSuccess",
+		Expr:   expr,
+		For:    "5m",
+		Labels: map[string]string{"severity": "critical"},
+		Annotations: map[string]string{
+			"summary":     "No successful backup for schedule {{ $labels.namespace }}/{{ $labels.schedule }}",
+			"description": "Last {{ $labels.engine }} backup of schedule {{ $labels.namespace }}/{{ $labels.schedule }} was completed more than two schedule intervals ago.",
+		},
+	}
+}
+
+func stuckRule(expr string) Rule {
+	return Rule{
+		Alert:  "BackupStuck",
+		Expr:   expr,
+		For:    "5m",
+		Labels: map[string]string{"severity": "warning"},
+		Annotations: map[string]string{
+			"summary":     "Backup {{ $labels.namespace }}/{{ $labels.name }} is stuck in {{ $labels.phase }} phase",
+			"description": "Backup {{ $labels.namespace }}/{{ $labels.name }} of schedule {{ $labels.schedule }} is in {{ $labels.phase }} phase more than two schedule intervals.",
+		},
+	}
+}
+
+func staleRule(expr string) Rule {
+	return Rule{
+		Alert:  "BackupScheduleStale",
+		Expr:   expr,
+		Labels: map[string]string{"severity": "critical"},
+		Annotations: map[string]string{
+			"summary":     "Backups of schedule {{ $labels.namespace }}/{{ $labels.schedule }} are stale",
+			"description": "Newest completed {{ $labels.engine }} backup of schedule {{ $labels.namespace }}/{{ $labels.schedule }} is older than its maxBackupAge.",
+		},
+	}
+}
+
+func retentionRule(selector string) Rule {
+	deletions := fmt.Sprintf("sum by (%s) (increase(%s{%s}[1d]))", scheduleLabels, metrics.RetentionDeletionsName, selector)
+	completions := fmt.Sprintf("sum by (%s) (increase(%s{%s}[1d]))", scheduleLabels, metrics.PhaseTransitionsName, joinSelectors(selector, `phase="Completed"`))
+
+	return Rule{
+		Alert:  "BackupRetentionOutpacesCreation",
+		Expr:   fmt.Sprintf("%s > (%s or %s * 0)", deletions, completions, deletions),
+		For:    "1h",
+		Labels: map[string]string{"severity": "warning"},
+		Annotations: map[string]string{
+			"summary":     "Retention of schedule {{ $labels.namespace }}/{{ $labels.schedule }} deletes backups faster than they are created",
+			"description": "Retention of schedule {{ $labels.namespace }}/{{ $labels.schedule }} deleted more backups than were completed during last day.",
+		},
+	}
+}
+
+func failedRule(selector string) Rule {
+	return Rule{
+		Alert: "BackupFailed",
+		Expr: fmt.Sprintf("sum by (%s, phase) (increase(%s{%s}[1h])) > 0",
+			scheduleLabels, metrics.PhaseTransitionsName, joinSelectors(selector, `phase=~"Failed|CreateFailed|UploadFailed"`)),
+		Labels: map[string]string{"severity": "warning"},
+		Annotations: map[string]string{
+			"summary":     "Backup of schedule {{ $labels.namespace }}/{{ $labels.schedule }} failed",
+			"description": "Backup of schedule {{ $labels.namespace }}/{{ $labels.schedule }} moved to {{ $labels.phase }} phase during last hour.",
+		},
+	}
+}
+
+func joinSelectors(selectors ...string) string {
+	var out string
+	for _, selector := range selectors {
+		if selector == "" {
+			continue
+		}
+
+		if out != "" {
+			out += ", "
+		}
+
+		out += selector
+	}
+
+	return out
+}
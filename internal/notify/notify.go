@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// EventCompleted is sent when backup is completed
+	EventCompleted = "Completed"
+
+	// EventFailed is sent when backup is moved to one of failed phases
+	EventFailed = "Failed"
+
+	// EventStale is sent when newest completed backup of schedule is older than allowed
+	EventStale = "Stale"
+
+	// EventFresh is sent when stale schedule got new completed backup
+	EventFresh = "Fresh"
+
+	// EventRetentionDeleted is sent when backup is deleted by schedule retention
+	EventRetentionDeleted = "RetentionDeleted"
+)
+
+// Event is backup or backup schedule state change notification
+type Event struct {
+	Reason    string    `json:"reason"`
+	Engine    string    `json:"engine"`
+	Schedule  string    `json:"schedule,omitempty"`
+	Namespace string    `json:"namespace"`
+	Backup    string    `json:"backup,omitempty"`
+	Phase     string    `json:"phase,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Size      int64     `json:"size,omitempty"`
+	Duration  float64   `json:"durationSeconds,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// Title returns short human readable event description
+func (e Event) Title() string {
+	if e.Backup != "" {
+		return fmt.Sprintf("%s backup %s/%s: %s", e.Engine, e.Namespace, e.Backup, e.Reason)
+	}
+
+	return fmt.Sprintf("%s backup schedule %s/%s: %s", e.Engine, e.Namespace, e.Schedule, e.Reason)
+}
+
+// Text returns human readable event details
+func (e Event) Text() string {
+	text := e.Title()
+	if e.Schedule != "" && e.Backup != "" {
+		text += fmt.Sprintf("\nschedule: %s", e.Schedule)
+	}
+	if e.Phase != "" {
+		text += fmt.Sprintf("\nphase: %s", e.Phase)
+	}
+	if e.Size > 0 {
+		text += fmt.Sprintf("\nsize: %d bytes", e.Size)
+	}
+	if e.Duration > 0 {
+		text += fmt.Sprintf("\nduration: %s", time.Duration(e.Duration*float64(time.Second)).Truncate(time.Second))
+	}
+	if e.Message != "" {
+		text += fmt.Sprintf("\nmessage: %s", e.Message)
+	}
+	if e.Error != "" {
+		text += fmt.Sprintf("\nerror: %s", e.Error)
+	}
+
+	return text
+}
+
+// Notifier sends events to notification sinks
+type Notifier interface {
+	Notify(ctx context.Context, e Event) error
+}
+
+// Sink delivers event to single destination
+type Sink interface {
+	Send(ctx context.Context, e Event) error
+}
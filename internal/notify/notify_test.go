@@ -0,0 +1,156 @@
+package notify_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sputnik-systems/backups-operator/internal/notify"
+)
+
+var event = notify.Event{
+	Reason:    notify.EventFailed,
+	Engine:    "clickhouse",
+	Schedule:  "daily",
+	Namespace: "default",
+	Backup:    "daily-1",
+	Phase:     "UploadFailed",
+	Error:     "access denied",
+	Size:      1024,
+	Duration:  90,
+	Time:      time.Now(),
+}
+
+func TestWebhookSend(t *testing.T) {
+	var got notify.Event
+	var token string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer s.Close()
+
+	w := &notify.Webhook{URL: s.URL, Headers: map[string]string{"Authorization": "Bearer token"}}
+	if err := w.Send(context.Background(), event); err != nil {
+		t.Fatalf("failed to send webhook: %s", err)
+	}
+
+	if got.Backup != event.Backup || got.Error != event.Error || got.Size != event.Size || got.Duration != event.Duration {
+		t.Fatalf("unexpected payload %+v", got)
+	}
+
+	if token != "Bearer token" {
+		t.Fatalf("expected authorization header, got %q", token)
+	}
+}
+
+func TestSlackSend(t *testing.T) {
+	var got map[string]string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer s.Close()
+
+	sl := &notify.Slack{URL: s.URL, Channel: "#backups"}
+	if err := sl.Send(context.Background(), event); err != nil {
+		t.Fatalf("failed to send slack message: %s", err)
+	}
+
+	if got["channel"] != "#backups" || !strings.Contains(got["text"], "error: access denied") {
+		t.Fatalf("unexpected payload %+v", got)
+	}
+
+	s.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, "no_team")
+	})
+
+	if err := sl.Send(context.Background(), event); err == nil || !strings.Contains(err.Error(), "no_team") {
+		t.Fatalf("expected status code error, got %v", err)
+	}
+}
+
+func TestSMTPSend(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer ln.Close()
+
+	messages := make(chan string, 1)
+	go serveSMTP(ln, messages)
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+
+	s := &notify.SMTP{Host: host, Port: p, From: "backups@example.com", To: []string{"ops@example.com"}}
+	if err := s.Send(context.Background(), event); err != nil {
+		t.Fatalf("failed to send email: %s", err)
+	}
+
+	msg := <-messages
+	if !strings.Contains(msg, "Subject: clickhouse backup default/daily-1: Failed") || !strings.Contains(msg, "size: 1024 bytes") {
+		t.Fatalf("unexpected message %q", msg)
+	}
+}
+
+// serveSMTP is minimal smtp server stand-in, which accepts single message
+func serveSMTP(ln net.Listener, messages chan<- string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "220 localhost ESMTP\r\n")
+
+	var data strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			fmt.Fprint(conn, "250 localhost\r\n")
+		case cmd == "DATA":
+			fmt.Fprint(conn, "354 end data with <CR><LF>.<CR><LF>\r\n")
+
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+
+				if line == ".\r\n" {
+					break
+				}
+
+				data.WriteString(line)
+			}
+
+			messages <- data.String()
+			fmt.Fprint(conn, "250 OK\r\n")
+		case cmd == "QUIT":
+			fmt.Fprint(conn, "221 bye\r\n")
+
+			return
+		default:
+			fmt.Fprint(conn, "250 OK\r\n")
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultTimeout is single notification sending timeout
+const defaultTimeout = 10 * time.Second
+
+// Webhook posts event as json to given url
+type Webhook struct {
+	URL     string
+	Headers map[string]string
+}
+
+func (w *Webhook) Send(ctx context.Context, e Event) error {
+	return postJSON(ctx, w.URL, w.Headers, e)
+}
+
+// Slack posts event text to slack or mattermost compatible incoming webhook
+type Slack struct {
+	URL      string
+	Channel  string
+	Username string
+}
+
+func (s *Slack) Send(ctx context.Context, e Event) error {
+	payload := struct {
+		Text     string `json:"text"`
+		Channel  string `json:"channel,omitempty"`
+		Username string `json:"username,omitempty"`
+	}{
+		Text:     e.Text(),
+		Channel:  s.Channel,
+		Username: s.Username,
+	}
+
+	return postJSON(ctx, s.URL, nil, payload)
+}
+
+// SMTP sends event text by email
+type SMTP struct {
+	Host     string
+	Port     int
+	From     string
+	To       []string
+	Username string
+	Password string
+}
+
+func (s *SMTP) Send(ctx context.Context, e Event) error {
+	if len(s.To) == 0 {
+		return fmt.Errorf("recipients list is empty")
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	msg := &bytes.Buffer{}
+	fmt.Fprintf(msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(msg, "To: %s\r\n", strings.Join(s.To, ", "))
+	fmt.Fprintf(msg, "Subject: %s\r\n", e.Title())
+	fmt.Fprintf(msg, "Date: %s\r\n", e.Time.Format(time.RFC1123Z))
+	fmt.Fprintf(msg, "Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	fmt.Fprintf(msg, "%s\r\n", strings.ReplaceAll(e.Text(), "\n", "\r\n"))
+
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+
+	// net/smtp does not support context, so sending is bounded by timeout only
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, s.From, s.To, msg.Bytes())
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(defaultTimeout):
+		return fmt.Errorf("failed to send email: timed out")
+	}
+}
+
+func postJSON(ctx context.Context, url string, headers map[string]string, payload interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to generate request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4*1024))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
+	"strings"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -30,11 +32,15 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	backupsv1alpha1 "github.com/sputnik-systems/backups-operator/api/v1alpha1"
 	"github.com/sputnik-systems/backups-operator/controllers"
+	"github.com/sputnik-systems/backups-operator/controllers/factory"
+	"github.com/sputnik-systems/backups-operator/internal/inventory"
+	"github.com/sputnik-systems/backups-operator/internal/tracing"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -54,11 +60,44 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var prometheusRules bool
+	var tracingOpts tracing.Options
+	var watchNamespaces string
+	var credentialsServiceAccount string
+	var enableAPI bool
+	var operatorCredentials bool
+	var credentialsFilePaths string
+	var externalRestoreTargets bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&prometheusRules, "prometheus-rules", false,
+		"Enable PrometheusRule reconciliation with alerts for each backup schedule. "+
+			"Requires prometheus-operator CRDs installed.")
+	flag.StringVar(&tracingOpts.Endpoint, "otlp-endpoint", "",
+		"OTLP http traces receiver host:port. Tracing is disabled if empty.")
+	flag.BoolVar(&tracingOpts.Insecure, "otlp-insecure", false, "Disable OTLP receiver tls.")
+	flag.StringVar(&tracingOpts.ServiceName, "otlp-service-name", "backups-operator", "Service name of exported traces.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
+		"Comma separated list of namespaces watched by operator. All namespaces are watched if empty. "+
+			"ClusterBackupSchedule and BackupPolicy controllers are disabled, when it is set.")
+	flag.StringVar(&credentialsServiceAccount, "credentials-service-account", "",
+		"Name of service account in backup object namespace, which is impersonated to read credentials secrets. "+
+			"Operator service account is used if empty.")
+	flag.BoolVar(&operatorCredentials, "operator-credentials", false,
+		"Allow dgraph objects to use operator environment and web identity storage credentials. "+
+			"Any user, who can create dgraph objects, may use them then.")
+	flag.StringVar(&credentialsFilePaths, "credentials-file-paths", "",
+		"Comma separated list of operator pod paths, where dgraph objects may read storage credentials files from. "+
+			"File credentials are disabled if empty.")
+	flag.BoolVar(&externalRestoreTargets, "external-restore-targets", false,
+		"Allow ClickHouseRestore targets, which are not cluster services, e.g. ip addresses and external hosts. "+
+			"Namespace of such target is unknown, so any restore object may overwrite its data.")
+	flag.BoolVar(&enableAPI, "enable-api", false,
+		"Serve backups inventory api and web ui on metrics endpoint under /api/ and /ui/ paths. "+
+			"Api requests are authenticated by bearer token and authorized with token user permissions.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -67,57 +106,165 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	ctx := ctrl.SetupSignalHandler()
+
+	shutdownTracing, err := tracing.Setup(ctx, tracingOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Error(err, "problem shutting down tracing")
+		}
+	}()
+
+	cfg := ctrl.GetConfigOrDie()
+
+	mgrOpts := ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
 		Port:                   9443,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "94227f08.sputnik.systems",
-	})
+	}
+
+	var namespaces []string
+	if watchNamespaces != "" {
+		namespaces = strings.Split(watchNamespaces, ",")
+		mgrOpts.NewCache = cache.MultiNamespacedCacheBuilder(namespaces)
+
+		setupLog.Info("watching namespaces", "namespaces", namespaces)
+	}
+
+	mgr, err := ctrl.NewManager(cfg, mgrOpts)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	factory.SetHooksConfig(cfg)
+
+	if credentialsServiceAccount != "" {
+		factory.SetCredentialsImpersonation(cfg, credentialsServiceAccount)
+	}
+
+	var filePaths []string
+	if credentialsFilePaths != "" {
+		filePaths = strings.Split(credentialsFilePaths, ",")
+	}
+	factory.SetOperatorCredentials(operatorCredentials, filePaths)
+	factory.SetExternalRestoreTargets(externalRestoreTargets)
+
+	notifier := factory.NewNotifier(mgr.GetClient(), ctrl.Log.WithName("notifier"))
+
 	// start cron background job
 	cmgr := cron.New()
 	cmgr.Start()
 
 	if err = (&controllers.DgraphBackupReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Notifier: notifier,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DgraphBackup")
 		os.Exit(1)
 	}
 	if err = (&controllers.DgraphBackupScheduleReconciler{
-		Client:    mgr.GetClient(),
-		Scheme:    mgr.GetScheme(),
-		Cron:      cmgr,
-		StartedAt: metav1.Now(),
+		Client:          mgr.GetClient(),
+		Scheme:          mgr.GetScheme(),
+		Cron:            cmgr,
+		StartedAt:       metav1.Now(),
+		PrometheusRules: prometheusRules,
+		Notifier:        notifier,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DgraphBackupSchedule")
 		os.Exit(1)
 	}
 	if err = (&controllers.ClickHouseBackupReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Notifier: notifier,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ClickHouseBackup")
 		os.Exit(1)
 	}
 	if err = (&controllers.ClickHouseBackupScheduleReconciler{
+		Client:          mgr.GetClient(),
+		Scheme:          mgr.GetScheme(),
+		Cron:            cmgr,
+		StartedAt:       metav1.Now(),
+		PrometheusRules: prometheusRules,
+		Notifier:        notifier,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClickHouseBackupSchedule")
+		os.Exit(1)
+	}
+	if err = (&controllers.ClickHouseBackupImportReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClickHouseBackupImport")
+		os.Exit(1)
+	}
+	if err = (&controllers.DgraphBackupImportReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DgraphBackupImport")
+		os.Exit(1)
+	}
+	if err = (&controllers.ClickHouseRestoreReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClickHouseRestore")
+		os.Exit(1)
+	}
+	if err = (&controllers.DgraphRestoreReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DgraphRestore")
+		os.Exit(1)
+	}
+	if err = (&controllers.RestoreScheduleReconciler{
 		Client:    mgr.GetClient(),
 		Scheme:    mgr.GetScheme(),
 		Cron:      cmgr,
 		StartedAt: metav1.Now(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "ClickHouseBackupSchedule")
+		setupLog.Error(err, "unable to create controller", "controller", "RestoreSchedule")
 		os.Exit(1)
 	}
+	// cluster scoped objects are not cached, when only some namespaces are watched
+	if len(namespaces) == 0 {
+		if err = (&controllers.ClusterBackupScheduleReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClusterBackupSchedule")
+			os.Exit(1)
+		}
+		if err = (&controllers.BackupPolicyReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "BackupPolicy")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
+	if enableAPI {
+		h := inventory.NewHandler(mgr.GetClient(), ctrl.Log.WithName("api"))
+		if err := h.Register(mgr.AddMetricsExtraHandler); err != nil {
+			setupLog.Error(err, "unable to set up api")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -128,7 +275,7 @@ func main() {
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}